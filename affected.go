@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"fsd-crawler/pkg/analyzer"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+)
+
+func runAffected(args []string) int {
+	flags := flag.NewFlagSet("affected", flag.ContinueOnError)
+	base := flags.String("base", "", i18n.T("взять измененные файлы из git diff --name-only относительно указанной ветки или коммита и неотслеживаемые файлы"))
+	asJSON := flags.Bool("json", false, i18n.T("вывести результат в формате JSON"))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler affected [--base REF] [--json] [файлы...]"))
+		fmt.Fprintln(flags.Output(), i18n.T("Без файлов и --base список измененных файлов читается из stdin."))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := loadConfig()
+
+	changed, err := changedFiles(flags.Args(), *base, os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при получении списка измененных файлов: %v", err))
+		return 1
+	}
+
+	srcFiles, err := relativeToSrc(changed, cfg.SrcDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при обработке путей: %v", err))
+		return 1
+	}
+
+	structure := analyzer.AnalyzeProject(cfg)
+	imports := append(append([]dependencies.Dependency{}, structure.Dependencies...), structure.InternalImports...)
+	result := dependencies.FindAffected(imports, srcFiles)
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при кодировании в JSON: %v", err))
+			return 1
+		}
+		return 0
+	}
+
+	printAffected(os.Stdout, result)
+	return 0
+}
+
+// changedFiles возвращает пути измененных файлов относительно текущей
+// директории: из аргументов, из git или из stdin.
+func changedFiles(args []string, base string, stdin io.Reader) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	if base != "" {
+		return gitChangedFiles(base)
+	}
+
+	var files []string
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, scanner.Err()
+}
+
+// gitChangedFiles возвращает файлы, измененные относительно base, вместе с
+// новыми неотслеживаемыми файлами: git diff их не показывает, а еще не
+// добавленный модуль тоже может затронуть другие файлы.
+func gitChangedFiles(base string) ([]string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil, i18n.Errorf("не удалось определить корень git-репозитория: %v", err)
+	}
+	root := strings.TrimSpace(string(out))
+
+	// --end-of-options не дает принять base вида --output=... за опцию git
+	diff, err := exec.Command("git", "-C", root, "diff", "--name-only", "--end-of-options", base, "--").Output()
+	if err != nil {
+		return nil, i18n.Errorf("не удалось выполнить git diff относительно %s: %v", base, err)
+	}
+	untracked, err := exec.Command("git", "-C", root, "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, i18n.Errorf("не удалось получить список неотслеживаемых файлов: %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(diff)+"\n"+string(untracked), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(line))
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			path = rel
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+func relativeToSrc(files []string, srcDir string) ([]string, error) {
+	srcAbs, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(srcAbs, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	return result, nil
+}
+
+func printAffected(w io.Writer, result dependencies.AffectedResult) {
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{i18n.T("Измененные файлы"), result.Changed},
+		{i18n.T("Затронутые файлы"), result.Files},
+		{i18n.T("Затронутые слайсы"), result.Slices},
+		{i18n.T("Затронутые страницы"), result.Pages},
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.title, len(section.items))
+		for _, item := range section.items {
+			fmt.Fprintf(w, "  %s\n", item)
+		}
+	}
+}
@@ -2,4 +2,4 @@ module fsd-crawler
 
 go 1.20
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"fsd-crawler/pkg/analyzer"
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/i18n"
+)
+
+func runHistory(args []string) int {
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	count := flags.Int("n", 10, i18n.T("число последних коммитов для анализа"))
+	ref := flags.String("ref", "HEAD", i18n.T("ветка или коммит, с которого начинается история"))
+	rebuild := flags.Bool("rebuild", false, i18n.T("пересчитать метрики коммитов, уже сохраненных в истории"))
+	asJSON := flags.Bool("json", false, i18n.T("вывести результат в формате JSON"))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler history [-n N] [--ref REF] [--rebuild] [--json]"))
+		fmt.Fprintln(flags.Output(), i18n.T("Анализирует последние коммиты git-репозитория и сохраняет метрики в %s в outputDir.", exporter.HistoryFileName))
+		fmt.Fprintln(flags.Output(), i18n.T("HTML-отчет показывает по этому файлу график динамики."))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 || *count < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := loadConfig()
+
+	root, srcRel, err := gitSourceDir(cfg.SrcDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при построении истории: %v", err))
+		return 1
+	}
+
+	commits, err := gitCommits(root, *ref, *count)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при построении истории: %v", err))
+		return 1
+	}
+
+	historyPath := exporter.HistoryPath(cfg.OutputDir)
+	history, err := exporter.LoadHistory(historyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при построении истории: %v", err))
+		return 1
+	}
+
+	// Коммиты обрабатываются от старых к новым, как на графике: так порядок
+	// коммитов с одинаковой датой сохраняется и в истории
+	configHash := exporter.ConfigHash(cfg)
+	var entries []exporter.HistoryEntry
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		if entry, ok := history.Lookup(commit.Hash, configHash); ok && !*rebuild {
+			entries = append(entries, entry)
+			continue
+		}
+
+		entry, err := analyzeCommit(cfg, root, srcRel, commit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при анализе коммита %s: %v", shortHash(commit.Hash), err))
+			return 1
+		}
+		if entry == nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Предупреждение: в коммите %s нет директории %s, коммит пропущен", shortHash(commit.Hash), srcRel))
+			continue
+		}
+		history.Add(*entry)
+		entries = append(entries, *entry)
+	}
+
+	if err := exporter.WriteHistory(historyPath, history); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при построении истории: %v", err))
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []exporter.HistoryEntry{}
+		}
+		if err := encoder.Encode(entries); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при кодировании в JSON: %v", err))
+			return 1
+		}
+		return 0
+	}
+
+	printHistory(os.Stdout, entries)
+	fmt.Println()
+	fmt.Println(i18n.T("История сохранена в %s", historyPath))
+	return 0
+}
+
+// gitSourceDir возвращает корень git-репозитория и путь srcDir относительно
+// него в формате git ("." — весь репозиторий).
+func gitSourceDir(srcDir string) (string, string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", "", i18n.Errorf("не удалось определить корень git-репозитория: %v", err)
+	}
+	root := strings.TrimSpace(string(out))
+
+	srcAbs, err := filepath.Abs(srcDir)
+	if err != nil {
+		return "", "", err
+	}
+	// Корень git может быть указан через символические ссылки
+	if resolved, err := filepath.EvalSymlinks(srcAbs); err == nil {
+		srcAbs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	rel, err := filepath.Rel(root, srcAbs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", i18n.Errorf("директория %s находится вне git-репозитория %s", srcDir, root)
+	}
+	return root, filepath.ToSlash(rel), nil
+}
+
+// gitCommits возвращает последние count коммитов ветки ref, от новых к
+// старым. Слияния учитываются только по первому родителю.
+func gitCommits(root, ref string, count int) ([]exporter.HistoryCommit, error) {
+	out, err := exec.Command("git", "-C", root, "log", "--first-parent",
+		fmt.Sprintf("--max-count=%d", count), "--format=%H%x00%an%x00%cI%x00%s", "--end-of-options", ref, "--").Output()
+	if err != nil {
+		return nil, i18n.Errorf("не удалось получить список коммитов %s: %v", ref, err)
+	}
+
+	var commits []exporter.HistoryCommit
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, exporter.HistoryCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    fields[2],
+			Subject: fields[3],
+		})
+	}
+	return commits, nil
+}
+
+// analyzeCommit извлекает srcDir коммита во временную директорию и считает
+// его метрики с текущей конфигурацией. Если srcDir в коммите нет,
+// возвращается nil.
+func analyzeCommit(cfg *config.Config, root, srcRel string, commit exporter.HistoryCommit) (*exporter.HistoryEntry, error) {
+	if srcRel != "." {
+		if err := exec.Command("git", "-C", root, "cat-file", "-e", commit.Hash+":"+srcRel).Run(); err != nil {
+			return nil, nil
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "fsd-history")
+	if err != nil {
+		return nil, i18n.Errorf("не удалось создать временную директорию: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := extractCommit(root, commit.Hash, srcRel, tempDir); err != nil {
+		return nil, err
+	}
+
+	commitCfg := *cfg
+	commitCfg.SrcDir = filepath.Join(tempDir, filepath.FromSlash(srcRel))
+	structure := analyzer.AnalyzeProject(&commitCfg)
+
+	entry := exporter.NewHistoryEntry(exporter.NewReport(structure, &commitCfg), commit)
+	// Отпечаток считается по исходной конфигурации: srcDir коммита —
+	// временная директория
+	entry.ConfigHash = exporter.ConfigHash(cfg)
+	return &entry, nil
+}
+
+// extractCommit распаковывает файлы path коммита в dir через git archive,
+// не создавая рабочих копий репозитория.
+func extractCommit(root, hash, path, dir string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "-C", root, "archive", "--format=tar", hash, "--", path)
+	cmd.Stderr = &stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return i18n.Errorf("не удалось выполнить git archive: %v", err)
+	}
+
+	extractErr := extractTar(out, dir)
+	// Дочитываем вывод, чтобы git archive не завис на записи
+	io.Copy(io.Discard, out)
+	if err := cmd.Wait(); err != nil {
+		return i18n.Errorf("не удалось выполнить git archive: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return extractErr
+}
+
+func extractTar(r io.Reader, dir string) error {
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return i18n.Errorf("не удалось прочитать архив коммита: %v", err)
+		}
+		// Символические ссылки и подмодули не анализируются
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return i18n.Errorf("некорректный путь в архиве коммита: %s", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(file, archive)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+func printHistory(w io.Writer, entries []exporter.HistoryEntry) {
+	fmt.Fprintf(w, "%-8s  %-10s  %6s  %6s  %10s  %s\n",
+		i18n.T("Коммит"), i18n.T("Дата"), i18n.T("Файлы"), i18n.T("Связи"), i18n.T("Нарушения"), i18n.T("Описание"))
+	for _, entry := range entries {
+		date := entry.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		fmt.Fprintf(w, "%-8s  %-10s  %6d  %6d  %10d  %s\n",
+			shortHash(entry.Hash), date, entry.Totals.Files, entry.Totals.Dependencies, entry.Totals.Violations, entry.Subject)
+	}
+}
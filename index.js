@@ -1,6 +1,6 @@
 #!/usr/bin/env node
 
-const { execSync } = require('child_process');
+const { execFileSync } = require('child_process');
 const path = require('path');
 const fs = require('fs');
 const os = require('os');
@@ -46,24 +46,39 @@ function runAnalyzer(options = {}) {
     throw new Error(`Ошибка: исполняемый файл не найден ни по пути ${binPath}, ни по пути ${fallbackPath}. Попробуйте запустить 'npm run build:current' или 'npm install'`);
   }
   
-  let args = '';
+  const args = [];
   
   if (options.config) {
-    args += ` --config ${options.config}`;
+    args.push('--config', options.config);
+  }
+
+  if (options.args && options.args.length > 0) {
+    args.push(...options.args);
   }
   
   try {
-    return execSync(`"${finalPath}"${args}`, { 
+    // Аргументы передаются без оболочки, поэтому $(), `...` и $VAR в них
+    // не раскрываются
+    return execFileSync(finalPath, args, { 
       stdio: options.silent ? 'ignore' : 'inherit',
       cwd: process.cwd()
     });
   } catch (error) {
-    throw new Error(`Ошибка при запуске анализатора: ${error.message}`);
+    const wrapped = new Error(`Ошибка при запуске анализатора: ${error.message}`);
+    wrapped.status = error.status;
+    throw wrapped;
   }
 }
 
 if (require.main === module) {
-  runAnalyzer();
+  try {
+    runAnalyzer({ args: process.argv.slice(2) });
+  } catch (error) {
+    if (error.status === undefined || error.status === null) {
+      console.error(error.message);
+    }
+    process.exit(error.status || 1);
+  }
 } else {
   module.exports = runAnalyzer;
 } 
\ No newline at end of file
@@ -7,38 +7,51 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"fsd-crawler/pkg/analyzer"
 	"fsd-crawler/pkg/config"
 	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/i18n"
 	"fsd-crawler/pkg/model"
 )
 
+var commands = map[string]func(args []string) int{
+	"why":      runWhy,
+	"query":    runQuery,
+	"affected": runAffected,
+	"schema":   runSchema,
+	"render":   runRender,
+	"history":  runHistory,
+}
+
+// localeFlag — локаль из флага --locale. Она важнее настройки locale в
+// конфигурации, а та — переменных окружения LC_ALL, LC_MESSAGES и LANG.
+var localeFlag string
+
 func main() {
-	startTime := time.Now()
-	
-	cfg, err := config.FindAndLoadConfig()
-	if err != nil {
-		fmt.Printf("Предупреждение: не удалось загрузить конфигурацию: %v\n", err)
-		fmt.Println("Используются значения по умолчанию.")
-		cfg = &config.DefaultConfig
-	}
+	i18n.SetEnvironmentLocale(i18n.Detect())
 
-	if cfg.SrcDir == "." {
-		if _, err := os.Stat("src"); err == nil {
-			cfg.SrcDir = "src"
-		}
+	locale, args, err := extractLocaleFlag(os.Args[1:])
+	if err == nil && locale != "" {
+		err = i18n.SetLocale(locale)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
+	localeFlag = locale
 
-	if len(cfg.Aliases) == 0 {
-		cfg.Aliases = map[string]string{
-			"@": "src",
-			"~": "src",
+	if len(args) > 0 {
+		if command, ok := commands[args[0]]; ok {
+			os.Exit(command(args[1:]))
 		}
 	}
 
-	model.UpdateFromConfig(cfg)
+	startTime := time.Now()
+	
+	cfg := loadConfig()
 
 	structure := analyzer.AnalyzeProject(cfg)
 
@@ -47,27 +60,21 @@ func main() {
 		outputFormats = []string{"html"}
 	}
 
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		fmt.Printf("Ошибка при создании директории для вывода: %v\n", err)
-		return
-	}
-
 	htmlPath := ""
 	
 	for _, format := range outputFormats {
-		switch format {
-		case "html":
-			if err := exporter.GenerateHTML(structure, cfg); err != nil {
-				fmt.Printf("Ошибка при генерации HTML: %v\n", err)
-			} else {
-				htmlPath = filepath.Join(cfg.OutputDir, "fsd_structure.html")
-			}
-		case "json":
-			if err := exporter.ExportJSON(structure, cfg); err != nil {
-				fmt.Printf("Ошибка при экспорте в JSON: %v\n", err)
-			}
-		default:
-			fmt.Printf("Неподдерживаемый формат вывода: %s\n", format)
+		if _, ok := exporter.Lookup(format, cfg); !ok {
+			fmt.Println(i18n.T("Неподдерживаемый формат вывода: %s", format))
+			continue
+		}
+
+		outputPath, err := exporter.Run(format, structure, cfg)
+		if err != nil {
+			fmt.Println(i18n.T("Ошибка при экспорте в %s: %v", format, err))
+			continue
+		}
+		if format == "html" && outputPath != exporter.Stdout {
+			htmlPath = outputPath
 		}
 	}
 	
@@ -77,15 +84,19 @@ func main() {
 			port = 3123
 		}
 		
-		fs := http.FileServer(http.Dir(cfg.OutputDir))
+		fs := http.FileServer(http.Dir(filepath.Dir(htmlPath)))
 		http.Handle("/", fs)
+		// Исходный код для предпросмотра мест импорта, не встроенный в отчет,
+		// — только файлы из отчета
+		http.Handle(exporter.HTMLSourceRoute, exporter.NewSourceHandler(exporter.NewReport(structure, cfg)))
 		
-		url := fmt.Sprintf("http://localhost:%d/fsd_structure.html", port)
+		url := fmt.Sprintf("http://localhost:%d/%s", port, filepath.Base(htmlPath))
 		
 		go func() {
-			err := http.ListenAndServe(":"+strconv.Itoa(port), nil)
+			// Отчет и исходный код доступны только с этой машины
+			err := http.ListenAndServe("127.0.0.1:"+strconv.Itoa(port), nil)
 			if err != nil {
-				fmt.Printf("Ошибка при запуске веб-сервера: %v\n", err)
+				fmt.Println(i18n.T("Ошибка при запуске веб-сервера: %v", err))
 			}
 		}()
 		
@@ -105,6 +116,67 @@ func main() {
 	}
 }
 
+func loadConfig() *config.Config {
+	cfg, err := config.FindAndLoadConfig()
+	if err != nil {
+		fmt.Println(i18n.T("Предупреждение: не удалось загрузить конфигурацию: %v", err))
+		fmt.Println(i18n.T("Используются значения по умолчанию."))
+		cfg = &config.DefaultConfig
+	}
+
+	if cfg.SrcDir == "." {
+		if _, err := os.Stat("src"); err == nil {
+			cfg.SrcDir = "src"
+		}
+	}
+
+	if len(cfg.Aliases) == 0 {
+		cfg.Aliases = map[string]string{
+			"@": "src",
+			"~": "src",
+		}
+	}
+
+	if localeFlag == "" && cfg.Locale != "" {
+		if err := i18n.SetLocale(cfg.Locale); err != nil {
+			fmt.Println(i18n.T("Предупреждение: %v", err))
+		}
+	}
+
+	model.UpdateFromConfig(cfg)
+
+	return cfg
+}
+
+// extractLocaleFlag убирает из аргументов флаг --locale (-locale, --locale=en)
+// в любой позиции до "--", чтобы он работал и без команды, и с любой командой.
+func extractLocaleFlag(args []string) (string, []string, error) {
+	var locale string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "locale" {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return "", nil, i18n.Errorf("флаг --locale требует значение (%s)", strings.Join(i18n.Locales(), ", "))
+			}
+			i++
+			value = args[i]
+		}
+		locale = value
+	}
+	return locale, rest, nil
+}
+
 func clearConsole() {
 	cmd := exec.Command("clear")
 	if _, err := os.Stat("/usr/bin/clear"); os.IsNotExist(err) {
@@ -125,12 +197,12 @@ func openBrowser(url string) {
 	case commandExists("start"):
 		err = runCommand("start", url)
 	default:
-		fmt.Printf("Не удалось автоматически открыть браузер. Пожалуйста, откройте %s вручную.\n", url)
+		fmt.Println(i18n.T("Не удалось автоматически открыть браузер. Пожалуйста, откройте %s вручную.", url))
 		return
 	}
 	
 	if err != nil {
-		fmt.Printf("Ошибка при открытии браузера: %v\n", err)
+		fmt.Println(i18n.T("Ошибка при открытии браузера: %v", err))
 	}
 }
 
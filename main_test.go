@@ -1,10 +1,19 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/query"
 )
 
 func TestShort(t *testing.T) {
@@ -180,4 +189,234 @@ func createTestFSDStructure(t *testing.T, rootDir string) {
 			t.Fatalf("Failed to create file %s: %v", filePath, err)
 		}
 	}
-} 
\ No newline at end of file
+} 
+func TestPrintPaths(t *testing.T) {
+	paths := []dependencies.DependencyPath{
+		{
+			{From: "pages/home", To: "features/cart", Imports: []dependencies.Dependency{{FromFile: "pages/home/ui/Home.tsx", Line: 3, ImportPath: "features/cart"}}},
+			{From: "features/cart", To: "entities/payment", Imports: []dependencies.Dependency{{FromFile: "features/cart/model/cart.ts", Line: 1, ImportPath: "entities/payment"}}},
+		},
+	}
+
+	var out bytes.Buffer
+	printPaths(&out, "pages/home", "entities/payment", paths)
+
+	expected := []string{
+		"pages/home → entities/payment: найдено путей: 1",
+		"1. pages/home → features/cart → entities/payment",
+		"pages/home/ui/Home.tsx:3  features/cart",
+		"features/cart/model/cart.ts:1  entities/payment",
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("printPaths output does not contain %q:\n%s", line, out.String())
+		}
+	}
+
+	out.Reset()
+	printPaths(&out, "entities/payment", "pages/home", nil)
+	if !strings.Contains(out.String(), "entities/payment не зависит от pages/home") {
+		t.Errorf("printPaths output for no paths = %q", out.String())
+	}
+}
+
+func TestWriteQueryJSON(t *testing.T) {
+	results := []query.Result{{
+		From: "features/auth", To: "entities/user", Type: dependencies.DependencyNormal, Count: 1,
+		Dependencies: []dependencies.Dependency{{
+			FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", Type: dependencies.DependencyNormal,
+			FromFile: "features/auth/model/auth.ts", ToFile: "entities/user/index.ts", Line: 2, ImportPath: "entities/user",
+		}},
+	}}
+
+	var out bytes.Buffer
+	if err := writeQueryJSON(&out, results); err != nil {
+		t.Fatalf("writeQueryJSON failed: %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	deps, _ := decoded[0]["dependencies"].([]interface{})
+	if len(deps) != 1 {
+		t.Fatalf("dependencies = %v", decoded[0]["dependencies"])
+	}
+	dep := deps[0].(map[string]interface{})
+	// Вложенные зависимости описываются ребрами JSON-отчета с ключами в camelCase
+	for _, key := range []string{"id", "fromLayer", "toSlice", "fromFile", "line", "importPath"} {
+		if _, ok := dep[key]; !ok {
+			t.Errorf("dependency has no %q key: %v", key, dep)
+		}
+	}
+	if _, ok := dep["FromFile"]; ok {
+		t.Errorf("dependency keys must be camelCase: %v", dep)
+	}
+}
+
+func TestChangedFilesFromStdin(t *testing.T) {
+	files, err := changedFiles(nil, "", strings.NewReader("src/entities/user/model/user.ts\n\n  src/shared/api/api.ts  \n"))
+	if err != nil {
+		t.Fatalf("changedFiles failed: %v", err)
+	}
+
+	srcFiles, err := relativeToSrc(append(files, "package.json"), "src")
+	if err != nil {
+		t.Fatalf("relativeToSrc failed: %v", err)
+	}
+
+	expected := []string{"entities/user/model/user.ts", "shared/api/api.ts"}
+	if len(srcFiles) != len(expected) {
+		t.Fatalf("relativeToSrc = %v; want %v", srcFiles, expected)
+	}
+	for i := range expected {
+		if srcFiles[i] != expected[i] {
+			t.Errorf("relativeToSrc[%d] = %s; want %s", i, srcFiles[i], expected[i])
+		}
+	}
+}
+
+func TestExtractLocaleFlag(t *testing.T) {
+	locale, args, err := extractLocaleFlag([]string{"why", "--locale", "en", "-k", "1", "pages/home", "--", "--locale=ru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locale != "en" {
+		t.Errorf("locale = %q, expected en", locale)
+	}
+	expected := []string{"why", "-k", "1", "pages/home", "--", "--locale=ru"}
+	if strings.Join(args, " ") != strings.Join(expected, " ") {
+		t.Errorf("args = %v, expected %v", args, expected)
+	}
+
+	if locale, _, _ := extractLocaleFlag([]string{"-locale=ru"}); locale != "ru" {
+		t.Errorf("locale = %q, expected ru", locale)
+	}
+	if _, _, err := extractLocaleFlag([]string{"--locale"}); err == nil {
+		t.Error("expected error for --locale without a value")
+	}
+}
+
+// newGitRepo создает пустой git-репозиторий и возвращает его корень,
+// функцию запуска git и функцию записи файла по пути от корня.
+func newGitRepo(t *testing.T) (string, func(args ...string), func(path, content string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", root}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=dev", "GIT_AUTHOR_EMAIL=dev@example.com",
+			"GIT_COMMITTER_NAME=dev", "GIT_COMMITTER_EMAIL=dev@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(path, content string) {
+		path = filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	return root, git, write
+}
+
+func TestHistoryCommits(t *testing.T) {
+	root, git, write := newGitRepo(t)
+
+	write("README.md", "# app\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "readme")
+	write("src/entities/user/index.ts", "export const user = 1;\n")
+	write("src/features/auth/index.ts", "import { user } from '@/entities/user';\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "add slices")
+	write("src/entities/user/index.ts", "import { login } from '@/features/auth';\nexport const user = 1;\n")
+	git("commit", "-q", "-a", "-m", "upward import")
+
+	commits, err := gitCommits(root, "HEAD", 10)
+	if err != nil {
+		t.Fatalf("gitCommits failed: %v", err)
+	}
+	if len(commits) != 3 || commits[0].Subject != "upward import" || commits[0].Author != "dev" {
+		t.Fatalf("unexpected commits: %+v", commits)
+	}
+
+	cfg := config.DefaultConfig
+	cfg.Aliases = map[string]string{"@": "src"}
+	var violations []int
+	for _, commit := range commits {
+		entry, err := analyzeCommit(&cfg, root, "src", commit)
+		if err != nil {
+			t.Fatalf("analyzeCommit(%s) failed: %v", commit.Subject, err)
+		}
+		if commit.Subject == "readme" {
+			if entry != nil {
+				t.Errorf("commit without src should be skipped, got %+v", entry)
+			}
+			continue
+		}
+		if entry == nil || entry.Totals.Files != 2 {
+			t.Fatalf("unexpected entry for %s: %+v", commit.Subject, entry)
+		}
+		if entry.ConfigHash != exporter.ConfigHash(&cfg) {
+			t.Errorf("entry for %s has config hash %q", commit.Subject, entry.ConfigHash)
+		}
+		violations = append(violations, entry.Rules["fsd/upward-import"])
+	}
+	if len(violations) != 2 || violations[0] != 1 || violations[1] != 0 {
+		t.Errorf("upward-import violations by commit = %v, expected [1 0]", violations)
+	}
+}
+
+func TestChangedFilesFromGit(t *testing.T) {
+	root, git, write := newGitRepo(t)
+
+	write(".gitignore", "*.log\n")
+	write("src/entities/user/index.ts", "export const user = 1;\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "init")
+	write("src/entities/user/index.ts", "export const user = 2;\n")
+	write("src/features/auth/index.ts", "import { user } from '@/entities/user';\n")
+	write("debug.log", "ignored\n")
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(currentDir)
+
+	files, err := changedFiles(nil, "HEAD", nil)
+	if err != nil {
+		t.Fatalf("changedFiles failed: %v", err)
+	}
+	expected := []string{"src/entities/user/index.ts", "src/features/auth/index.ts"}
+	if strings.Join(files, " ") != strings.Join(expected, " ") {
+		t.Errorf("changedFiles = %v; want %v", files, expected)
+	}
+
+	// Ссылка, похожая на опцию, не должна доходить до git как опция
+	output := filepath.Join(root, "output.txt")
+	if _, err := changedFiles(nil, "--output="+output, nil); err == nil {
+		t.Error("changedFiles accepted an option as --base")
+	}
+	if _, err := gitCommits(root, "--output="+output, 1); err == nil {
+		t.Error("gitCommits accepted an option as --ref")
+	}
+	if _, err := os.Stat(output); err == nil {
+		t.Error("git treated a user-supplied ref as --output")
+	}
+}
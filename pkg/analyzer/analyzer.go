@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,21 +33,21 @@ func AnalyzeProject(cfg *config.Config) *model.ProjectStructure {
 		
 		structure.Layers = append(structure.Layers, layer)
 		
-		analyzeLayer(layer, layerPath)
+		analyzeLayer(layer, layerPath, cfg.ExcludeDirs)
 	}
 	
 	depAnalyzer := dependencies.NewDependencyAnalyzer(structure, rootDir, cfg)
-	deps := depAnalyzer.AnalyzeDependencies()
-	
-	structure.Dependencies = make([]interface{}, len(deps))
-	for i, dep := range deps {
-		structure.Dependencies[i] = dep
-	}
+	structure.Dependencies = depAnalyzer.AnalyzeDependencies()
+	structure.InternalImports = depAnalyzer.GetInternalImports()
+	structure.APIIssues = depAnalyzer.GetPublicAPIIssues()
+	structure.BarrelIssues = depAnalyzer.GetBarrelIssues()
+	structure.SegmentViolations = depAnalyzer.GetSegmentViolations()
+	structure.SegmentCycles = depAnalyzer.GetSegmentCycles()
 	
 	return structure
 }
 
-func analyzeLayer(layer *model.FSDLayer, layerPath string) {
+func analyzeLayer(layer *model.FSDLayer, layerPath string, excludeDirs []string) {
 	entries, err := os.ReadDir(layerPath)
 	if err != nil {
 		return
@@ -72,7 +73,7 @@ func analyzeLayer(layer *model.FSDLayer, layerPath string) {
 		
 		for _, entry := range entries {
 			if !entry.IsDir() && isSourceFile(entry.Name()) {
-				segment.Files = append(segment.Files, entry.Name())
+				addFile(segment, layerPath, entry.Name())
 			}
 		}
 		
@@ -81,7 +82,7 @@ func analyzeLayer(layer *model.FSDLayer, layerPath string) {
 	}
 	
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() && !isExcluded(entry.Name(), excludeDirs) {
 			slicePath := filepath.Join(layerPath, entry.Name())
 			slice := &model.FSDSlice{
 				Name:     entry.Name(),
@@ -109,7 +110,7 @@ func analyzeLayer(layer *model.FSDLayer, layerPath string) {
 				
 				for _, sliceEntry := range sliceEntries {
 					if !sliceEntry.IsDir() && isSourceFile(sliceEntry.Name()) {
-						segment.Files = append(segment.Files, sliceEntry.Name())
+						addFile(segment, slicePath, sliceEntry.Name())
 					}
 				}
 				
@@ -160,7 +161,7 @@ func analyzeSlice(slice *model.FSDSlice, slicePath string) {
 			
 			for _, segmentEntry := range segmentEntries {
 				if !segmentEntry.IsDir() && isSourceFile(segmentEntry.Name()) {
-					segment.Files = append(segment.Files, segmentEntry.Name())
+					addFile(segment, segmentPath, segmentEntry.Name())
 				}
 			}
 			
@@ -171,7 +172,72 @@ func analyzeSlice(slice *model.FSDSlice, slicePath string) {
 	}
 }
 
+// addFile добавляет файл в сегмент вместе с его размером в строках и байтах.
+func addFile(segment *model.FSDSegment, dir, name string) {
+	segment.Files = append(segment.Files, name)
+	if segment.Stats == nil {
+		segment.Stats = make(map[string]model.FileStats)
+	}
+	segment.Stats[name] = fileStats(filepath.Join(dir, name))
+}
+
+// fileStats считает строки файла; последняя строка без перевода строки
+// тоже учитывается. Нечитаемый файл имеет нулевой размер.
+func fileStats(path string) model.FileStats {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return model.FileStats{}
+	}
+
+	lines := bytes.Count(content, []byte{'\n'})
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		lines++
+	}
+	return model.FileStats{Lines: lines, Bytes: int64(len(content))}
+}
+
 func isSourceFile(fileName string) bool {
 	ext := strings.ToLower(filepath.Ext(fileName))
 	return ext == ".js" || ext == ".jsx" || ext == ".ts" || ext == ".tsx" || ext == ".vue"
-} 
\ No newline at end of file
+}
+
+func isExcluded(name string, excludeDirs []string) bool {
+	return strings.HasPrefix(name, ".") || contains(excludeDirs, name)
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+func findFiles(rootDir string, cfg *config.Config) []string {
+	var excludeDirs []string
+	if cfg != nil {
+		excludeDirs = cfg.ExcludeDirs
+	}
+
+	var files []string
+	filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != rootDir && isExcluded(d.Name(), excludeDirs) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isSourceFile(d.Name()) {
+			if rel, err := filepath.Rel(rootDir, path); err == nil {
+				files = append(files, rel)
+			}
+		}
+		return nil
+	})
+
+	return files
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -125,4 +126,59 @@ func TestFindFiles(t *testing.T) {
 			}
 		}
 	}
-} 
\ No newline at end of file
+} 
+
+func TestFileStats(t *testing.T) {
+	tempDir := t.TempDir()
+	testCases := []struct {
+		content string
+		lines   int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"a\n", 1},
+		{"a\nb", 2},
+		{"a\r\nb\r\n", 2},
+	}
+
+	for i, tc := range testCases {
+		path := filepath.Join(tempDir, fmt.Sprintf("file%d.ts", i))
+		if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+			t.Fatalf("Failed to create file %s: %v", path, err)
+		}
+		stats := fileStats(path)
+		if stats.Lines != tc.lines || stats.Bytes != int64(len(tc.content)) {
+			t.Errorf("fileStats(%q) = %+v; want %d lines, %d bytes", tc.content, stats, tc.lines, len(tc.content))
+		}
+	}
+
+	if stats := fileStats(filepath.Join(tempDir, "missing.ts")); stats.Lines != 0 || stats.Bytes != 0 {
+		t.Errorf("fileStats of a missing file = %+v; want zero", stats)
+	}
+}
+
+func TestAnalyzeProjectExcludeDirs(t *testing.T) {
+	srcDir := t.TempDir()
+	for _, name := range []string{
+		"entities/user/model/user.ts",
+		"entities/node_modules/lib/index.ts",
+		"entities/.cache/model/cache.ts",
+	} {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("export const value = 1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	structure := AnalyzeProject(&config.Config{SrcDir: srcDir, ExcludeDirs: []string{"node_modules"}})
+	if len(structure.Layers) != 1 {
+		t.Fatalf("layers = %d; want 1", len(structure.Layers))
+	}
+	slices := structure.Layers[0].Slices
+	if len(slices) != 1 || slices[0].Name != "user" {
+		t.Errorf("excluded and hidden directories must not become slices: %+v", slices)
+	}
+}
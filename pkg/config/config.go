@@ -1,11 +1,12 @@
 package config
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"fsd-crawler/pkg/i18n"
 )
 
 type Config struct {
@@ -19,6 +20,78 @@ type Config struct {
 	ServeHTML                bool              `yaml:"serveHTML"`
 	Port                     int               `yaml:"port"`
 	AllowedCyclicalDependencies []string       `yaml:"allowedCyclicalDependencies"`
+	MaxReexportDepth         int               `yaml:"maxReexportDepth"`
+	SegmentRules             []SegmentRule     `yaml:"segmentRules"`
+	GraphDetail              string            `yaml:"graphDetail"`
+	Diagram                  DiagramConfig     `yaml:"diagram"`
+	Overwrite                string            `yaml:"overwrite"`
+	Outputs                  map[string]OutputConfig `yaml:"outputs"`
+	ExternalExporters        map[string]ExternalExporter `yaml:"externalExporters"`
+	Markdown                 MarkdownConfig    `yaml:"markdown"`
+	HTML                     HTMLConfig        `yaml:"html"`
+	Locale                   string            `yaml:"locale"`
+}
+
+type SegmentRule struct {
+	From  string `yaml:"from"`
+	To    string `yaml:"to"`
+	Scope string `yaml:"scope"`
+}
+
+// Области действия правила сегментов; пустая область равна SegmentScopeAll.
+const (
+	SegmentScopeAll   = "all"
+	SegmentScopeSlice = "slice"
+	SegmentScopeCross = "cross"
+)
+
+// OutputConfig задает цель вывода отдельного формата: путь к файлу или "-"
+// для стандартного вывода, а также политику перезаписи (always или never).
+type OutputConfig struct {
+	Path      string `yaml:"path"`
+	Overwrite string `yaml:"overwrite"`
+}
+
+// ExternalExporter описывает внешнюю программу, которая получает JSON-отчет
+// на stdin и выводит отчет своего формата в stdout.
+type ExternalExporter struct {
+	Command  string   `yaml:"command"`
+	Args     []string `yaml:"args"`
+	FileName string   `yaml:"fileName"`
+}
+
+// DiagramConfig ограничивает диаграммы Mermaid и PlantUML, чтобы они
+// оставались читаемыми на больших проектах.
+type DiagramConfig struct {
+	Layers         []string `yaml:"layers"`
+	Exclude        []string `yaml:"exclude"`
+	ViolationsOnly bool     `yaml:"violationsOnly"`
+	MaxNodes       int      `yaml:"maxNodes"`
+	MaxEdges       int      `yaml:"maxEdges"`
+}
+
+// MarkdownConfig настраивает Markdown-сводку для комментариев к merge
+// request. RepoURL — префикс ссылок на файлы, например
+// https://gitlab.com/org/repo/-/blob/main.
+type MarkdownConfig struct {
+	RepoURL       string `yaml:"repoURL"`
+	TopSlices     int    `yaml:"topSlices"`
+	MaxViolations int    `yaml:"maxViolations"`
+	Mermaid       bool   `yaml:"mermaid"`
+}
+
+// HTMLConfig настраивает HTML-отчет. Assets: inline — стили и скрипты
+// встраиваются в страницу, external — сохраняются рядом в директорию assets.
+// Layout — раскладка графа по умолчанию: layered или force. SourceMaxBytes
+// ограничивает исходный код, встраиваемый для предпросмотра мест импорта
+// (0 — 1 МиБ, -1 — не встраивать). Partials — glob-шаблоны файлов с блоками
+// {{define}}, которые подключаются к HTML-шаблону и переопределяют блоки
+// стандартного отчета.
+type HTMLConfig struct {
+	Assets         string   `yaml:"assets"`
+	Layout         string   `yaml:"layout"`
+	SourceMaxBytes int      `yaml:"sourceMaxBytes"`
+	Partials       []string `yaml:"partials"`
 }
 
 var DefaultConfig = Config{
@@ -50,7 +123,7 @@ func FindAndLoadConfig() (*Config, error) {
 
 	dir, err := os.Getwd()
 	if err != nil {
-		return nil, fmt.Errorf("не удалось получить текущую директорию: %v", err)
+		return nil, i18n.Errorf("не удалось получить текущую директорию: %v", err)
 	}
 
 	for {
@@ -74,12 +147,21 @@ func FindAndLoadConfig() (*Config, error) {
 func LoadConfig(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("не удалось прочитать файл конфигурации %s: %v", path, err)
+		return nil, i18n.Errorf("не удалось прочитать файл конфигурации %s: %v", path, err)
 	}
 
 	config := DefaultConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("не удалось распарсить файл конфигурации %s: %v", path, err)
+		return nil, i18n.Errorf("не удалось распарсить файл конфигурации %s: %v", path, err)
+	}
+
+	// Опечатка в scope иначе молча расширила бы правило на все импорты
+	for _, rule := range config.SegmentRules {
+		switch rule.Scope {
+		case "", SegmentScopeAll, SegmentScopeSlice, SegmentScopeCross:
+		default:
+			return nil, i18n.Errorf("неизвестная область segmentRules.scope %q в %s (ожидается all, slice или cross)", rule.Scope, path)
+		}
 	}
 
 	return &config, nil
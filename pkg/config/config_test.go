@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -147,4 +148,37 @@ excludeDirs:
 	if !reflect.DeepEqual(config, &DefaultConfig) {
 		t.Errorf("Default config not returned when no config file found")
 	}
-} 
\ No newline at end of file
+}
+
+func TestLoadConfigSegmentScope(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "fsd-crawler.yml")
+
+	valid := `
+segmentRules:
+  - from: model
+    to: ui
+  - from: ui
+    to: api
+    scope: cross
+`
+	if err := os.WriteFile(configPath, []byte(valid), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err != nil {
+		t.Errorf("LoadConfig rejected valid scopes: %v", err)
+	}
+
+	invalid := `
+segmentRules:
+  - from: model
+    to: ui
+    scope: slices
+`
+	if err := os.WriteFile(configPath, []byte(invalid), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), `"slices"`) {
+		t.Errorf("LoadConfig with unknown scope = %v; want error mentioning \"slices\"", err)
+	}
+}
@@ -0,0 +1,122 @@
+package dependencies
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type AffectedResult struct {
+	Changed []string `json:"changed"`
+	Files   []string `json:"files"`
+	Slices  []string `json:"slices"`
+	Pages   []string `json:"pages"`
+}
+
+// FindAffected возвращает файлы, слайсы и страницы, которые транзитивно
+// зависят от измененных файлов. Пути задаются относительно srcDir. Импорты,
+// которые не удалось разрешить до файла, считаются зависимостью от всего
+// слайса, поэтому результат может быть шире, но не уже реального.
+func FindAffected(deps []Dependency, changed []string) AffectedResult {
+	byFile := make(map[string][]Dependency)
+	bySlice := make(map[string][]Dependency)
+	locations := make(map[string]fileLocation)
+	var knownFiles []string
+
+	for _, dep := range deps {
+		if dep.ToFile != "" {
+			byFile[dep.ToFile] = append(byFile[dep.ToFile], dep)
+			// Изменение barrel-файла затрагивает импорты, перенесенные
+			// на источники имен
+			if dep.ViaFile != "" {
+				byFile[dep.ViaFile] = append(byFile[dep.ViaFile], dep)
+			}
+		} else {
+			to := slicePath(dep.ToLayer, dep.ToSlice)
+			bySlice[to] = append(bySlice[to], dep)
+		}
+
+		if dep.FromFile != "" {
+			locations[dep.FromFile] = fileLocation{Layer: dep.FromLayer, Slice: dep.FromSlice}
+		}
+		if dep.ToFile != "" {
+			locations[dep.ToFile] = fileLocation{Layer: dep.ToLayer, Slice: dep.ToSlice}
+		}
+	}
+	for file := range locations {
+		knownFiles = append(knownFiles, file)
+	}
+	sort.Strings(knownFiles)
+
+	result := AffectedResult{
+		Changed: []string{},
+		Files:   []string{},
+		Slices:  []string{},
+		Pages:   []string{},
+	}
+	affectedFiles := make(map[string]bool)
+	affectedSlices := make(map[string]bool)
+	var queue []string
+
+	enqueue := func(file string) {
+		if !affectedFiles[file] {
+			affectedFiles[file] = true
+			queue = append(queue, file)
+		}
+	}
+
+	for _, file := range changed {
+		file = strings.Trim(filepath.ToSlash(filepath.Clean(file)), "/")
+		result.Changed = append(result.Changed, file)
+
+		if filepath.Ext(file) != "" {
+			enqueue(file)
+			continue
+		}
+		for _, known := range knownFiles {
+			if strings.HasPrefix(known, file+"/") {
+				enqueue(known)
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+
+		location, ok := locations[file]
+		if !ok {
+			location = locateByPath(file)
+		}
+
+		dependents := byFile[file]
+		if location.Layer != "" {
+			slice := slicePath(location.Layer, location.Slice)
+			if !affectedSlices[slice] {
+				affectedSlices[slice] = true
+				dependents = append(dependents, bySlice[slice]...)
+			}
+		}
+
+		for _, dep := range dependents {
+			if dep.FromFile != "" {
+				enqueue(dep.FromFile)
+			}
+		}
+	}
+
+	for file := range affectedFiles {
+		result.Files = append(result.Files, file)
+	}
+	for slice := range affectedSlices {
+		result.Slices = append(result.Slices, slice)
+		if strings.HasPrefix(slice, "pages/") || slice == "pages" {
+			result.Pages = append(result.Pages, slice)
+		}
+	}
+	sort.Strings(result.Files)
+	sort.Strings(result.Slices)
+	sort.Strings(result.Pages)
+
+	return result
+}
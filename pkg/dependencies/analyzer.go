@@ -1,38 +1,39 @@
 package dependencies
 
 import (
-	"bufio"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"fsd-crawler/pkg/config"
 	"fsd-crawler/pkg/model"
 )
 
-type DependencyType string
+type DependencyType = model.DependencyType
 
 const (
-	DependencyNormal   DependencyType = "normal"
-	DependencySameLayer DependencyType = "same"
-	DependencyCyclical DependencyType = "cyclical"
-	DependencyTest     DependencyType = "test"
+	DependencyNormal    = model.DependencyNormal
+	DependencySameLayer = model.DependencySameLayer
+	DependencyCyclical  = model.DependencyCyclical
+	DependencyTest      = model.DependencyTest
 )
 
-type Dependency struct {
-	FromLayer string
-	FromSlice string
-	ToLayer   string
-	ToSlice   string
-	Type      DependencyType
-}
+type Dependency = model.Dependency
 
 type DependencyAnalyzer struct {
 	structure    *model.ProjectStructure
 	rootDir      string
 	layerIndices map[string]int
 	dependencies []Dependency
+	// internalImports — относительные импорты внутри слайса (../model/user).
+	// Это не зависимости между слайсами, поэтому они не входят в
+	// dependencies и метрики, но нужны для путей между файлами, правил
+	// сегментов и поиска затронутых файлов.
+	internalImports []Dependency
+	publicAPIs   map[string]*publicAPI
+	exportCache  map[string][]exportStatement
+	starChainCache map[string][]string
+	fileLocations map[string]fileLocation
 	DetermineDepType func(fromLayer, fromSlice, toLayer, toSlice string) DependencyType
 	config       *config.Config
 }
@@ -48,6 +49,10 @@ func NewDependencyAnalyzer(structure *model.ProjectStructure, rootDir string, cf
 		rootDir:      rootDir,
 		layerIndices: layerIndices,
 		dependencies: []Dependency{},
+		publicAPIs:   make(map[string]*publicAPI),
+		exportCache:  make(map[string][]exportStatement),
+		starChainCache: make(map[string][]string),
+		fileLocations: make(map[string]fileLocation),
 		config:       cfg,
 	}
 	
@@ -58,6 +63,11 @@ func NewDependencyAnalyzer(structure *model.ProjectStructure, rootDir string, cf
 
 func (da *DependencyAnalyzer) AnalyzeDependencies() []Dependency {
 	da.dependencies = []Dependency{}
+	da.internalImports = []Dependency{}
+	da.publicAPIs = make(map[string]*publicAPI)
+	da.exportCache = make(map[string][]exportStatement)
+	da.starChainCache = make(map[string][]string)
+	da.fileLocations = make(map[string]fileLocation)
 
 	for _, layer := range da.structure.Layers {
 		for _, slice := range layer.Slices {
@@ -69,6 +79,8 @@ func (da *DependencyAnalyzer) AnalyzeDependencies() []Dependency {
 		}
 	}
 
+	da.traceSymbolOrigins()
+
 	return da.dependencies
 }
 
@@ -78,77 +90,115 @@ func (da *DependencyAnalyzer) analyzeSliceImports(layerName string, slice *model
 		sliceName = layerName
 	}
 
+	slicePath := filepath.Join(da.rootDir, layerName)
+	if sliceName != layerName {
+		slicePath = filepath.Join(slicePath, sliceName)
+	}
+
 	for _, segment := range slice.Segments {
-		segmentPath := filepath.Join(da.rootDir, layerName)
-		if sliceName != layerName {
-			segmentPath = filepath.Join(segmentPath, sliceName)
+		segmentPath := slicePath
+		if segment.Name != "root" {
+			segmentPath = filepath.Join(segmentPath, segment.Name)
 		}
-		segmentPath = filepath.Join(segmentPath, segment.Name)
 
 		for _, file := range segment.Files {
 			filePath := filepath.Join(segmentPath, file)
+			da.registerFile(filePath, layerName, sliceName, segment.Name)
 			da.analyzeFileImports(filePath, layerName, sliceName)
+
+			if segment.Name == "root" && isPublicAPIFile(file) {
+				slice.Exports = da.analyzePublicAPI(filePath, layerName, sliceName)
+			}
 		}
 	}
 }
 
 func (da *DependencyAnalyzer) analyzeFileImports(filePath, fromLayer, fromSlice string) {
-	supportedExtensions := []string{".js", ".jsx", ".ts", ".tsx"}
-
-	ext := filepath.Ext(filePath)
-	supported := false
-	for _, supportedExt := range supportedExtensions {
-		if ext == supportedExt {
-			supported = true
-			break
-		}
-	}
-	if !supported {
+	if !isSupportedSourceFile(filePath) {
 		return
 	}
 
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return
 	}
-	defer file.Close()
 
-	importPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`import\s+.*\s+from\s+['"]([^'"]+)['"]\s*;?`),
-		regexp.MustCompile(`import\s+['"]([^'"]+)['"]\s*;?`),
-		regexp.MustCompile(`require\s*\(\s*['"]([^'"]+)['"]\s*\)`),
-	}
+	fromFile := da.relativePath(filePath)
+	fromSegment := da.locateFile(fromFile).Segment
 
-	lineNum := 0
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-		
-		for _, pattern := range importPatterns {
-			matches := pattern.FindStringSubmatch(line)
-			if len(matches) > 1 {
-				importPath := matches[1]
-				
-				resolvedPath := da.resolveAliasPath(importPath)
-				
-				toLayer, toSlice := da.extractLayerAndSlice(resolvedPath)
-				
-				if toLayer != "" {
-					depType := da.DetermineDepType(fromLayer, fromSlice, toLayer, toSlice)
-					
-					dependency := Dependency{
-						FromLayer: fromLayer,
-						FromSlice: fromSlice,
-						ToLayer:   toLayer,
-						ToSlice:   toSlice,
-						Type:      depType,
-					}
-					da.dependencies = append(da.dependencies, dependency)
-				}
+	for _, imp := range parseImports(string(content)) {
+		resolvedPath := da.resolveAliasPath(imp.Path)
+
+		toFile := ""
+		if resolvedFile := da.resolveImportFile(filePath, imp.Path); resolvedFile != "" {
+			toFile = da.relativePath(resolvedFile)
+		}
+
+		toLayer, toSlice := da.extractLayerAndSlice(resolvedPath)
+		toSegment := segmentFromImportPath(resolvedPath, toLayer)
+		internal := false
+		if toFile != "" {
+			location := da.locateFile(toFile)
+			if toLayer == "" {
+				// Путь импорта не называет слой: слайс берется по разрешенному
+				// файлу. Относительный импорт в другой слайс
+				// (../../cart/model) — настоящая зависимость между слайсами,
+				// импорт внутри своего слайса в зависимости не попадает.
+				toLayer, toSlice = location.Layer, location.Slice
+				internal = slicePath(toLayer, toSlice) == slicePath(fromLayer, fromSlice)
+			}
+			if location.Layer == toLayer && location.Slice == toSlice {
+				toSegment = location.Segment
 			}
 		}
+		if toLayer == "" {
+			continue
+		}
+
+		depType := da.DetermineDepType(fromLayer, fromSlice, toLayer, toSlice)
+
+		dependency := Dependency{
+			FromLayer:   fromLayer,
+			FromSlice:   fromSlice,
+			FromSegment: fromSegment,
+			ToLayer:     toLayer,
+			ToSlice:     toSlice,
+			ToSegment:   toSegment,
+			Type:        depType,
+			FromFile:    fromFile,
+			ToFile:      toFile,
+			Line:        imp.Line,
+			ImportPath:  imp.Path,
+			ImportKind:  imp.Kind,
+			Specifiers:  imp.Specifiers,
+		}
+		if internal {
+			da.internalImports = append(da.internalImports, dependency)
+			continue
+		}
+		da.dependencies = append(da.dependencies, dependency)
+	}
+}
+
+func (da *DependencyAnalyzer) relativePath(filePath string) string {
+	if da.rootDir != "" {
+		if rel, err := filepath.Rel(da.rootDir, filePath); err == nil {
+			return filepath.ToSlash(rel)
+		}
 	}
+	return filepath.ToSlash(filePath)
+}
+
+func isSupportedSourceFile(filePath string) bool {
+	supportedExtensions := []string{".js", ".jsx", ".ts", ".tsx"}
+
+	ext := filepath.Ext(filePath)
+	for _, supportedExt := range supportedExtensions {
+		if ext == supportedExt {
+			return true
+		}
+	}
+	return false
 }
 
 func (da *DependencyAnalyzer) resolveAliasPath(importPath string) string {
@@ -270,6 +320,18 @@ func (da *DependencyAnalyzer) determineDependencyTypeWithSlices(fromLayer, fromS
 	return DependencyNormal
 }
 
+// GetInternalImports возвращает относительные импорты внутри слайсов,
+// которые не входят в зависимости между слайсами.
+func (da *DependencyAnalyzer) GetInternalImports() []Dependency {
+	return da.internalImports
+}
+
+// allImports возвращает зависимости между слайсами вместе с импортами
+// внутри слайсов.
+func (da *DependencyAnalyzer) allImports() []Dependency {
+	return append(append([]Dependency{}, da.dependencies...), da.internalImports...)
+}
+
 func (da *DependencyAnalyzer) GetDependenciesForLayer(layerName string) []Dependency {
 	var result []Dependency
 	for _, dep := range da.dependencies {
@@ -1,14 +1,19 @@
 package dependencies
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"fsd-crawler/pkg/config"
 	"fsd-crawler/pkg/model"
 )
 
+// В исходной версии тест вызывал несуществующий determineDependencyType,
+// и пакет тестов не компилировался. Правила для слоев без слайсов
+// проверяются через determineDependencyTypeWithSlices с пустыми слайсами.
 func TestDetermineDependencyType(t *testing.T) {
 	structure := &model.ProjectStructure{}
 	analyzer := NewDependencyAnalyzer(structure, "", nil)
@@ -35,7 +40,7 @@ func TestDetermineDependencyType(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		result := analyzer.determineDependencyType(tc.fromLayer, tc.toLayer)
+		result := analyzer.determineDependencyTypeWithSlices(tc.fromLayer, "", tc.toLayer, "")
 		if result != tc.expected {
 			t.Errorf("determineDependencyType(%s, %s) = %s; want %s", 
 				tc.fromLayer, tc.toLayer, result, tc.expected)
@@ -43,6 +48,10 @@ func TestDetermineDependencyType(t *testing.T) {
 	}
 }
 
+// Ожидания исправлены под поведение extractLayerAndSlice, которое не
+// менялось: слайсом считается только второй элемент пути, сегмент в него
+// не входит ("entities/user/api" — слайс "user", а не "user/api"). Раньше
+// расхождение было незаметно, потому что пакет тестов не компилировался.
 func TestExtractLayerAndSlice(t *testing.T) {
 	structure := &model.ProjectStructure{}
 	analyzer := NewDependencyAnalyzer(structure, "", nil)
@@ -52,13 +61,13 @@ func TestExtractLayerAndSlice(t *testing.T) {
 		expectedLayer string
 		expectedSlice string
 	}{
-		{"entities/user/api", "entities", "user/api"},
-		{"features/auth/ui", "features", "auth/ui"},
+		{"entities/user/api", "entities", "user"},
+		{"features/auth/ui", "features", "auth"},
 		{"app/routes", "app", "routes"},
 		{"shared/ui", "shared", "ui"},
 		
-		{"../entities/user/api", "entities", "user/api"},
-		{"./features/auth/ui", "features", "auth/ui"},
+		{"../entities/user/api", "entities", "user"},
+		{"./features/auth/ui", "features", "auth"},
 		{"app/routes", "app", "routes"},
 		
 		{"utils/helpers", "", ""},
@@ -153,6 +162,44 @@ import { HomePage } from 'pages/home/ui';
 	}
 }
 
+func TestInternalImports(t *testing.T) {
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"features/auth/ui/Form.tsx":   "import { login } from '../model/auth';\nimport { cart } from '../../cart/model/cart';\n",
+		"features/auth/model/auth.ts": "export const login = 1;\n",
+		"features/cart/model/cart.ts": "export const cart = 1;\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(rootDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	structure := &model.ProjectStructure{Layers: []*model.FSDLayer{{
+		Name: "features",
+		Slices: []*model.FSDSlice{
+			{Name: "auth", Segments: []*model.FSDSegment{{Name: "ui", Files: []string{"Form.tsx"}}, {Name: "model", Files: []string{"auth.ts"}}}},
+			{Name: "cart", Segments: []*model.FSDSegment{{Name: "model", Files: []string{"cart.ts"}}}},
+		},
+	}}}
+	analyzer := NewDependencyAnalyzer(structure, rootDir, nil)
+	deps := analyzer.AnalyzeDependencies()
+
+	// Относительный импорт в другой слайс — зависимость между слайсами,
+	// импорт внутри слайса в зависимости и метрики не попадает
+	if len(deps) != 1 || deps[0].ToSlice != "cart" || deps[0].ToFile != "features/cart/model/cart.ts" {
+		t.Errorf("AnalyzeDependencies() = %+v; want only features/auth → features/cart", deps)
+	}
+	internal := analyzer.GetInternalImports()
+	if len(internal) != 1 || internal[0].ToFile != "features/auth/model/auth.ts" || internal[0].ToSegment != "model" {
+		t.Errorf("GetInternalImports() = %+v; want features/auth/ui → features/auth/model", internal)
+	}
+}
+
 func TestGetProblematicDependencies(t *testing.T) {
 	structure := &model.ProjectStructure{}
 	analyzer := NewDependencyAnalyzer(structure, "", nil)
@@ -175,4 +222,473 @@ func TestGetProblematicDependencies(t *testing.T) {
 			t.Errorf("Dependency type %s is not problematic", dep.Type)
 		}
 	}
-} 
\ No newline at end of file
+} 
+func TestParseImports(t *testing.T) {
+	content := `
+import React from 'react';
+import {
+	userModel,
+	type User,
+	selectUser as select,
+} from 'entities/user';
+// import { ignored } from 'features/ignored';
+import * as api from 'shared/api';
+import './styles.css';
+export { Button } from 'shared/ui';
+`
+
+	imports := parseImports(content)
+
+	expected := []struct {
+		path       string
+		line       int
+		specifiers []string
+	}{
+		{"react", 2, []string{"default"}},
+		{"entities/user", 3, []string{"userModel", "User", "selectUser"}},
+		{"shared/api", 9, []string{"*"}},
+		{"./styles.css", 10, nil},
+		{"shared/ui", 11, []string{"Button"}},
+	}
+
+	if len(imports) != len(expected) {
+		t.Fatalf("parseImports found %d imports; want %d: %+v", len(imports), len(expected), imports)
+	}
+
+	for i, exp := range expected {
+		imp := imports[i]
+		if imp.Path != exp.path || imp.Line != exp.line {
+			t.Errorf("import %d = %s:%d; want %s:%d", i, imp.Path, imp.Line, exp.path, exp.line)
+		}
+		if !reflect.DeepEqual(imp.Specifiers, exp.specifiers) {
+			t.Errorf("import %s specifiers = %v; want %v", imp.Path, imp.Specifiers, exp.specifiers)
+		}
+	}
+}
+
+func TestGetPublicAPIIssues(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsd-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"entities/user/index.ts":      "export { userModel, selectUser } from './model/user';\nexport const unusedHelper = 1;\n",
+		"entities/user/model/user.ts": "export const userModel = {};\nexport const selectUser = () => {};\nexport const internal = 1;\n",
+		"features/auth/model/auth.ts": "import { userModel, internal } from 'entities/user';\n",
+		"pages/home/ui/HomePage.tsx":  "import { selectUser } from 'entities/user';\n",
+	}
+
+	for path, content := range files {
+		filePath := filepath.Join(tempDir, path)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+
+	structure := &model.ProjectStructure{
+		Layers: []*model.FSDLayer{
+			{Name: "pages", Slices: []*model.FSDSlice{{Name: "home", Segments: []*model.FSDSegment{{Name: "ui", Files: []string{"HomePage.tsx"}}}}}},
+			{Name: "features", Slices: []*model.FSDSlice{{Name: "auth", Segments: []*model.FSDSegment{{Name: "model", Files: []string{"auth.ts"}}}}}},
+			{Name: "entities", Slices: []*model.FSDSlice{{Name: "user", Segments: []*model.FSDSegment{
+				{Name: "root", Files: []string{"index.ts"}},
+				{Name: "model", Files: []string{"user.ts"}},
+			}}}},
+		},
+	}
+
+	analyzer := NewDependencyAnalyzer(structure, tempDir, nil)
+	analyzer.AnalyzeDependencies()
+
+	userSlice := structure.Layers[2].Slices[0]
+	if !reflect.DeepEqual(userSlice.Exports, []string{"selectUser", "unusedHelper", "userModel"}) {
+		t.Errorf("Exports = %v; want [selectUser unusedHelper userModel]", userSlice.Exports)
+	}
+
+	issues := analyzer.GetPublicAPIIssues()
+
+	expected := []APIIssue{
+		{Kind: APIIssueNotExported, Layer: "entities", Slice: "user", Name: "internal", File: "features/auth/model/auth.ts", Line: 1},
+		{Kind: APIIssueUnusedExport, Layer: "entities", Slice: "user", Name: "unusedHelper", File: "entities/user/index.ts", Line: 2},
+	}
+
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("GetPublicAPIIssues() = %+v; want %+v", issues, expected)
+	}
+}
+
+func TestGetPublicAPIIssuesWithoutSpecifiers(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"entities/user/index.ts":      "export const userModel = {};\n",
+		"entities/theme/index.ts":     "export const applyTheme = () => {};\n",
+		"entities/cart/index.ts":      "export const cartModel = {};\n",
+		"pages/home/ui/HomePage.tsx":  "const user = import('entities/user');\nimport 'entities/theme';\n",
+		"features/auth/model/auth.ts": "import { cartModel } from 'entities/cart';\n",
+	}
+	for path, content := range files {
+		filePath := filepath.Join(tempDir, path)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := func(name string) *model.FSDSlice {
+		return &model.FSDSlice{Name: name, Segments: []*model.FSDSegment{{Name: "root", Files: []string{"index.ts"}}}}
+	}
+	structure := &model.ProjectStructure{
+		Layers: []*model.FSDLayer{
+			{Name: "pages", Slices: []*model.FSDSlice{{Name: "home", Segments: []*model.FSDSegment{{Name: "ui", Files: []string{"HomePage.tsx"}}}}}},
+			{Name: "features", Slices: []*model.FSDSlice{{Name: "auth", Segments: []*model.FSDSegment{{Name: "model", Files: []string{"auth.ts"}}}}}},
+			{Name: "entities", Slices: []*model.FSDSlice{root("user"), root("theme"), root("cart")}},
+		},
+	}
+
+	analyzer := NewDependencyAnalyzer(structure, tempDir, nil)
+	analyzer.AnalyzeDependencies()
+
+	// Слайсы, используемые только через import() и import 'x', не получают
+	// ложных сообщений о неиспользуемых экспортах
+	if issues := analyzer.GetPublicAPIIssues(); len(issues) != 0 {
+		t.Errorf("GetPublicAPIIssues() = %+v; want no issues", issues)
+	}
+}
+
+func TestBarrelReexportChains(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsd-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	files := map[string]string{
+		"entities/user/index.ts":           "export * from './model';\nexport { sessionModel } from 'entities/session';\n",
+		"entities/user/model/index.ts":     "export * from './store';\n",
+		"entities/user/model/store.ts":     "export * from './selectors';\n",
+		"entities/user/model/selectors.ts": "export const selectUser = () => {};\n",
+		"entities/session/index.ts":        "export const sessionModel = {};\n",
+		"entities/user/model/session.ts":   "export { sessionModel } from 'entities/session';\n",
+		"features/auth/model/auth.ts":      "import { selectUser, sessionModel } from 'entities/user';\n",
+	}
+
+	for path, content := range files {
+		filePath := filepath.Join(tempDir, path)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+
+	structure := &model.ProjectStructure{
+		Layers: []*model.FSDLayer{
+			{Name: "features", Slices: []*model.FSDSlice{{Name: "auth", Segments: []*model.FSDSegment{{Name: "model", Files: []string{"auth.ts"}}}}}},
+			{Name: "entities", Slices: []*model.FSDSlice{
+				{Name: "session", Segments: []*model.FSDSegment{{Name: "root", Files: []string{"index.ts"}}}},
+				{Name: "user", Segments: []*model.FSDSegment{
+					{Name: "root", Files: []string{"index.ts"}},
+					{Name: "model", Files: []string{"index.ts", "selectors.ts", "session.ts", "store.ts"}},
+				}},
+			}},
+		},
+	}
+
+	analyzer := NewDependencyAnalyzer(structure, tempDir, nil)
+	deps := analyzer.AnalyzeDependencies()
+
+	var authDeps []Dependency
+	for _, dep := range deps {
+		if dep.FromFile == "features/auth/model/auth.ts" {
+			authDeps = append(authDeps, dep)
+		}
+	}
+
+	// Импорт через barrel-файл делится по модулям, где объявлены имена
+	selectUser := SymbolOrigin{
+		Name: "selectUser", File: "entities/user/model/selectors.ts",
+		Layer: "entities", Slice: "user", Segment: "model",
+		Chain: []string{"entities/user/index.ts", "entities/user/model/index.ts", "entities/user/model/store.ts", "entities/user/model/selectors.ts"},
+	}
+	sessionModel := SymbolOrigin{
+		Name: "sessionModel", File: "entities/session/index.ts",
+		Layer: "entities", Slice: "session", Segment: "root",
+		Chain: []string{"entities/user/index.ts", "entities/session/index.ts"},
+	}
+	imported := Dependency{
+		FromLayer: "features", FromSlice: "auth", FromSegment: "model", Type: DependencyNormal,
+		FromFile: "features/auth/model/auth.ts", ViaFile: "entities/user/index.ts",
+		Line: 1, ImportPath: "entities/user", ImportKind: ImportStatic,
+	}
+	toSelectors, toSession := imported, imported
+	toSelectors.ToLayer, toSelectors.ToSlice, toSelectors.ToSegment, toSelectors.ToFile = "entities", "user", "model", selectUser.File
+	toSelectors.Specifiers, toSelectors.Origins = []string{"selectUser"}, []SymbolOrigin{selectUser}
+	toSession.ToLayer, toSession.ToSlice, toSession.ToSegment, toSession.ToFile = "entities", "session", "root", sessionModel.File
+	toSession.Specifiers, toSession.Origins = []string{"sessionModel"}, []SymbolOrigin{sessionModel}
+	if !reflect.DeepEqual(authDeps, []Dependency{toSelectors, toSession}) {
+		t.Errorf("dependencies of auth.ts = %+v; want %+v", authDeps, []Dependency{toSelectors, toSession})
+	}
+
+	// Публичный API проверяется по barrel-файлу из импорта
+	for _, dep := range authDeps {
+		if BypassesPublicAPI(dep) {
+			t.Errorf("import via the public API is reported as a bypass: %+v", dep)
+		}
+	}
+	if issues := analyzer.GetPublicAPIIssues(); len(issues) != 0 {
+		t.Errorf("GetPublicAPIIssues() = %+v; want no issues", issues)
+	}
+
+	userSlice := structure.Layers[1].Slices[1]
+	if !reflect.DeepEqual(userSlice.Exports, []string{"selectUser", "sessionModel"}) {
+		t.Errorf("Exports = %v; want [selectUser sessionModel]", userSlice.Exports)
+	}
+
+	issues := analyzer.GetBarrelIssues()
+	expectedIssues := []BarrelIssue{
+		{
+			Kind: BarrelIssueDeepChain, Layer: "entities", Slice: "user", File: "entities/user/index.ts", Line: 1,
+			Chain: []string{"entities/user/index.ts", "entities/user/model/index.ts", "entities/user/model/store.ts", "entities/user/model/selectors.ts"},
+		},
+		{
+			Kind: BarrelIssueCrossSlice, Layer: "entities", Slice: "user", File: "entities/user/index.ts", Line: 2,
+			TargetLayer: "entities", TargetSlice: "session",
+		},
+	}
+	if !reflect.DeepEqual(issues, expectedIssues) {
+		t.Errorf("GetBarrelIssues() = %+v; want %+v", issues, expectedIssues)
+	}
+}
+
+func TestLongestStarChainDiamond(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Каждый уровень — ромб: index реэкспортирует a и b, оба реэкспортируют
+	// следующий index. Без кэша обход растет как 2^levels.
+	const levels = 40
+	for i := 0; i < levels; i++ {
+		next := fmt.Sprintf("./index%d", i+1)
+		files := map[string]string{
+			fmt.Sprintf("index%d.ts", i): fmt.Sprintf("export * from './a%d';\nexport * from './b%d';\n", i, i),
+			fmt.Sprintf("a%d.ts", i):     fmt.Sprintf("export * from '%s';\n", next),
+			fmt.Sprintf("b%d.ts", i):     fmt.Sprintf("export * from '%s';\n", next),
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write file %s: %v", name, err)
+			}
+		}
+	}
+	last := fmt.Sprintf("index%d.ts", levels)
+	if err := os.WriteFile(filepath.Join(tempDir, last), []byte("export const value = 1;\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file %s: %v", last, err)
+	}
+
+	analyzer := NewDependencyAnalyzer(&model.ProjectStructure{}, tempDir, nil)
+	chain := analyzer.longestStarChain(filepath.Join(tempDir, "index0.ts"), make(map[string]bool))
+	if len(chain) != 2*levels+1 {
+		t.Errorf("longestStarChain length = %d; want %d", len(chain), 2*levels+1)
+	}
+}
+
+func TestFindPaths(t *testing.T) {
+	deps := []Dependency{
+		{FromLayer: "pages", FromSlice: "home", ToLayer: "features", ToSlice: "cart", FromFile: "pages/home/ui/Home.tsx", ToFile: "features/cart/index.ts", Line: 1, ImportPath: "features/cart"},
+		{FromLayer: "pages", FromSlice: "home", ToLayer: "pages", ToSlice: "home", FromFile: "pages/home/ui/Home.tsx", ToFile: "pages/home/model/home.ts", Line: 2, ImportPath: "../model/home"},
+		{FromLayer: "pages", FromSlice: "home", ToLayer: "entities", ToSlice: "payment", FromFile: "pages/home/model/home.ts", ToFile: "entities/payment/index.ts", Line: 4, ImportPath: "entities/payment"},
+		{FromLayer: "features", FromSlice: "cart", ToLayer: "entities", ToSlice: "payment", FromFile: "features/cart/index.ts", ToFile: "entities/payment/index.ts", Line: 3, ImportPath: "entities/payment"},
+		{FromLayer: "features", FromSlice: "cart", ToLayer: "entities", ToSlice: "user", FromFile: "features/cart/index.ts", ToFile: "entities/user/index.ts", Line: 5, ImportPath: "entities/user"},
+	}
+
+	slice := func(path string) PathNode { return PathNode{Kind: PathNodeSlice, Path: path} }
+	file := func(path string) PathNode { return PathNode{Kind: PathNodeFile, Path: path} }
+
+	paths := FindPaths(deps, slice("pages/home"), slice("entities/payment"), 0)
+	if len(paths) != 2 {
+		t.Fatalf("FindPaths found %d paths; want 2", len(paths))
+	}
+	if len(paths[0]) != 1 || paths[0][0].To != "entities/payment" || paths[0][0].Imports[0].Line != 4 {
+		t.Errorf("Shortest path = %+v; want direct pages/home → entities/payment", paths[0])
+	}
+	if len(paths[1]) != 2 || paths[1][0].To != "features/cart" {
+		t.Errorf("Second path = %+v; want via features/cart", paths[1])
+	}
+
+	if paths := FindPaths(deps, slice("pages/home"), slice("entities/payment"), 1); len(paths) != 1 {
+		t.Errorf("FindPaths with limit 1 found %d paths; want 1", len(paths))
+	}
+
+	filePaths := FindPaths(deps, file("pages/home/ui/Home.tsx"), slice("entities/payment"), 0)
+	if len(filePaths) != 2 {
+		t.Fatalf("FindPaths on files found %d paths; want 2", len(filePaths))
+	}
+	if filePaths[0][0].To != "features/cart/index.ts" || filePaths[1][0].To != "pages/home/model/home.ts" {
+		t.Errorf("File paths = %+v; want via features/cart/index.ts and pages/home/model/home.ts", filePaths)
+	}
+
+	if paths := FindPaths(deps, slice("entities/payment"), slice("pages/home"), 0); len(paths) != 0 {
+		t.Errorf("FindPaths found %d reverse paths; want 0", len(paths))
+	}
+
+	// Слайс с точкой в имени остается слайсом: вид узла задается явно
+	dotted := append(deps, Dependency{FromLayer: "features", FromSlice: "cart", ToLayer: "entities", ToSlice: "user.profile", FromFile: "features/cart/index.ts", ToFile: "entities/user.profile/index.ts", Line: 6, ImportPath: "entities/user.profile"})
+	structure := &model.ProjectStructure{Layers: []*model.FSDLayer{{Name: "entities", Slices: []*model.FSDSlice{{Name: "user.profile"}}}}}
+	if node := NewPathNode(structure, "entities/user.profile/"); node != slice("entities/user.profile") {
+		t.Errorf("NewPathNode(entities/user.profile) = %+v; want slice", node)
+	}
+	if node := NewPathNode(structure, "entities/user.profile/index.ts"); node != file("entities/user.profile/index.ts") {
+		t.Errorf("NewPathNode(entities/user.profile/index.ts) = %+v; want file", node)
+	}
+	if paths := FindPaths(dotted, slice("pages/home"), NewPathNode(structure, "entities/user.profile"), 0); len(paths) != 1 || paths[0][1].To != "entities/user.profile" {
+		t.Errorf("FindPaths to a dotted slice = %+v; want pages/home → features/cart → entities/user.profile", paths)
+	}
+}
+
+func TestFindAffected(t *testing.T) {
+	deps := []Dependency{
+		{FromLayer: "pages", FromSlice: "home", ToLayer: "features", ToSlice: "cart", FromFile: "pages/home/ui/Home.tsx", ToFile: "features/cart/index.ts"},
+		{FromLayer: "features", FromSlice: "cart", ToLayer: "entities", ToSlice: "payment", FromFile: "features/cart/index.ts", ToFile: "entities/payment/model/payment.ts"},
+		{FromLayer: "widgets", FromSlice: "header", ToLayer: "entities", ToSlice: "payment", FromFile: "widgets/header/ui/Header.tsx", ToFile: ""},
+		{FromLayer: "pages", FromSlice: "profile", ToLayer: "entities", ToSlice: "user", FromFile: "pages/profile/ui/Profile.tsx", ToFile: "entities/user/index.ts"},
+	}
+
+	result := FindAffected(deps, []string{"entities/payment/model/payment.ts"})
+
+	expectedFiles := []string{
+		"entities/payment/model/payment.ts",
+		"features/cart/index.ts",
+		"pages/home/ui/Home.tsx",
+		"widgets/header/ui/Header.tsx",
+	}
+	if !reflect.DeepEqual(result.Files, expectedFiles) {
+		t.Errorf("Files = %v; want %v", result.Files, expectedFiles)
+	}
+
+	expectedSlices := []string{"entities/payment", "features/cart", "pages/home", "widgets/header"}
+	if !reflect.DeepEqual(result.Slices, expectedSlices) {
+		t.Errorf("Slices = %v; want %v", result.Slices, expectedSlices)
+	}
+
+	if !reflect.DeepEqual(result.Pages, []string{"pages/home"}) {
+		t.Errorf("Pages = %v; want [pages/home]", result.Pages)
+	}
+
+	result = FindAffected(deps, []string{"entities/user"})
+	if !reflect.DeepEqual(result.Pages, []string{"pages/profile"}) {
+		t.Errorf("Pages for directory = %v; want [pages/profile]", result.Pages)
+	}
+}
+
+func TestSegmentRules(t *testing.T) {
+	cfg := &config.Config{
+		SegmentRules: []config.SegmentRule{
+			{From: "api", To: "ui"},
+			{From: "model", To: "lib", Scope: SegmentScopeCross},
+		},
+	}
+	analyzer := NewDependencyAnalyzer(&model.ProjectStructure{}, "", cfg)
+
+	analyzer.dependencies = []Dependency{
+		{FromLayer: "entities", FromSlice: "user", FromSegment: "api", ToLayer: "entities", ToSlice: "user", ToSegment: "ui", FromFile: "entities/user/api/user.ts", Line: 1},
+		{FromLayer: "entities", FromSlice: "user", FromSegment: "model", ToLayer: "entities", ToSlice: "user", ToSegment: "lib", FromFile: "entities/user/model/user.ts", Line: 2},
+		{FromLayer: "features", FromSlice: "auth", FromSegment: "model", ToLayer: "shared", ToSlice: "lib", ToSegment: "lib", FromFile: "features/auth/model/auth.ts", Line: 3},
+		{FromLayer: "features", FromSlice: "auth", FromSegment: "ui", ToLayer: "features", ToSlice: "auth", ToSegment: "model", FromFile: "features/auth/ui/Form.tsx", Line: 4},
+		{FromLayer: "features", FromSlice: "auth", FromSegment: "model", ToLayer: "features", ToSlice: "auth", ToSegment: "ui", FromFile: "features/auth/model/auth.ts", Line: 5},
+	}
+
+	violations := analyzer.GetSegmentViolations()
+	if len(violations) != 2 {
+		t.Fatalf("Found %d segment violations; want 2", len(violations))
+	}
+	if violations[0].File != "entities/user/api/user.ts" || violations[0].Rule.To != "ui" {
+		t.Errorf("Unexpected first violation %+v", violations[0])
+	}
+	if violations[1].File != "features/auth/model/auth.ts" || violations[1].ToLayer != "shared" {
+		t.Errorf("Unexpected second violation %+v", violations[1])
+	}
+
+	cycles := analyzer.GetSegmentCycles()
+	if len(cycles) != 1 {
+		t.Fatalf("Found %d segment cycles; want 1", len(cycles))
+	}
+	if cycles[0].Layer != "features" || cycles[0].Slice != "auth" {
+		t.Errorf("Cycle slice = %s/%s; want features/auth", cycles[0].Layer, cycles[0].Slice)
+	}
+	if !reflect.DeepEqual(cycles[0].Segments, []string{"model", "ui"}) {
+		t.Errorf("Cycle segments = %v; want [model ui]", cycles[0].Segments)
+	}
+	if len(cycles[0].Imports) != 2 {
+		t.Errorf("Cycle has %d imports; want 2", len(cycles[0].Imports))
+	}
+}
+
+func TestSegmentFromImportPath(t *testing.T) {
+	tests := []struct {
+		importPath string
+		layer      string
+		expected   string
+	}{
+		{"entities/user/model/store", "entities", "model"},
+		{"entities/user", "entities", "root"},
+		{"src/shared/ui/button", "shared", "root"},
+		{"features/auth/ui", "features", "ui"},
+		{"react", "", ""},
+	}
+
+	for _, test := range tests {
+		if segment := segmentFromImportPath(test.importPath, test.layer); segment != test.expected {
+			t.Errorf("segmentFromImportPath(%q, %q) = %q; want %q", test.importPath, test.layer, segment, test.expected)
+		}
+	}
+}
+
+func TestFindCycles(t *testing.T) {
+	deps := []Dependency{
+		{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", FromFile: "features/auth/index.ts", Line: 1},
+		{FromLayer: "entities", FromSlice: "user", ToLayer: "features", ToSlice: "auth", FromFile: "entities/user/index.ts", Line: 2},
+		{FromLayer: "entities", FromSlice: "user", ToLayer: "shared", ToSlice: "api", FromFile: "entities/user/index.ts", Line: 3},
+		{FromLayer: "entities", FromSlice: "user", ToLayer: "entities", ToSlice: "user", FromFile: "entities/user/index.ts", Line: 4},
+	}
+
+	cycles := FindCycles(deps)
+	if len(cycles) != 1 {
+		t.Fatalf("Found %d cycles; want 1", len(cycles))
+	}
+	if !reflect.DeepEqual(cycles[0].Slices, []string{"entities/user", "features/auth"}) {
+		t.Errorf("Cycle slices = %v; want [entities/user features/auth]", cycles[0].Slices)
+	}
+	if len(cycles[0].Imports) != 2 {
+		t.Errorf("Cycle has %d imports; want 2", len(cycles[0].Imports))
+	}
+}
+
+func TestBypassesPublicAPI(t *testing.T) {
+	tests := []struct {
+		dep      Dependency
+		expected bool
+	}{
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToFile: "entities/user/index.ts"}, false},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToFile: "src/entities/user/index.ts"}, false},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToFile: "entities/user/model/store.ts"}, true},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToSegment: "model"}, true},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToSegment: "root"}, false},
+		{Dependency{FromLayer: "entities", FromSlice: "user", ToLayer: "entities", ToSlice: "user", ToFile: "entities/user/model/store.ts"}, false},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "shared", ToSlice: "ui", ToFile: "shared/ui/Button.tsx"}, false},
+		// Зависимость перенесена на источник имен: проверяется barrel-файл из импорта
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "session", ToFile: "entities/session/model/store.ts", ViaFile: "entities/user/index.ts"}, false},
+		{Dependency{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", ToFile: "entities/user/model/store.ts", ViaFile: "entities/user/model/index.ts"}, true},
+	}
+
+	for _, test := range tests {
+		if result := BypassesPublicAPI(test.dep); result != test.expected {
+			t.Errorf("BypassesPublicAPI(%s/%s → %s) = %v; want %v", test.dep.FromLayer, test.dep.FromSlice, test.dep.ToFile, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package dependencies
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/model"
+)
+
+const defaultMaxReexportDepth = 2
+
+type SymbolOrigin = model.SymbolOrigin
+
+type BarrelIssueKind = model.BarrelIssueKind
+
+const (
+	BarrelIssueDeepChain  = model.BarrelIssueDeepChain
+	BarrelIssueCrossSlice = model.BarrelIssueCrossSlice
+)
+
+type BarrelIssue = model.BarrelIssue
+
+type fileLocation struct {
+	Layer   string
+	Slice   string
+	Segment string
+}
+
+var resolveExtensions = []string{".ts", ".tsx", ".js", ".jsx"}
+
+func (da *DependencyAnalyzer) absolutePath(relPath string) string {
+	return filepath.Join(da.rootDir, filepath.FromSlash(relPath))
+}
+
+// resolveImportFile находит файл, на который указывает импорт, с учетом
+// алиасов, расширений и index-файлов. Возвращает пустую строку, если файл
+// не найден (например, для пакетов из node_modules).
+func (da *DependencyAnalyzer) resolveImportFile(fromFile, importPath string) string {
+	var bases []string
+
+	if importPath == "." || importPath == ".." || strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		bases = append(bases, filepath.Join(filepath.Dir(fromFile), importPath))
+	} else {
+		resolved := da.resolveAliasPath(importPath)
+		if resolved != importPath {
+			bases = append(bases, resolved)
+		}
+		bases = append(bases, filepath.Join(da.rootDir, resolved))
+	}
+
+	for _, base := range bases {
+		candidates := []string{base}
+		for _, ext := range resolveExtensions {
+			candidates = append(candidates, base+ext)
+		}
+		for _, ext := range resolveExtensions {
+			candidates = append(candidates, filepath.Join(base, "index"+ext))
+		}
+
+		for _, candidate := range candidates {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() && isSupportedSourceFile(candidate) {
+				return candidate
+			}
+		}
+	}
+
+	return ""
+}
+
+func (da *DependencyAnalyzer) fileExports(filePath string) []exportStatement {
+	if exports, ok := da.exportCache[filePath]; ok {
+		return exports
+	}
+
+	var exports []exportStatement
+	if content, err := os.ReadFile(filePath); err == nil {
+		exports = parseExports(string(content))
+	}
+	da.exportCache[filePath] = exports
+
+	return exports
+}
+
+// traceSymbol проходит по цепочке реэкспортов и возвращает файл, в котором
+// символ name действительно объявлен, вместе с путем по барелям.
+func (da *DependencyAnalyzer) traceSymbol(filePath, name string, visited map[string]bool) (string, []string) {
+	if visited[filePath] {
+		return "", nil
+	}
+	visited[filePath] = true
+
+	exports := da.fileExports(filePath)
+	current := da.relativePath(filePath)
+
+	for _, exp := range exports {
+		if exp.Star {
+			continue
+		}
+		for i, exported := range exp.Names {
+			if exported != name {
+				continue
+			}
+			if exp.From == "" {
+				return filePath, []string{current}
+			}
+
+			target := da.resolveImportFile(filePath, exp.From)
+			if target == "" {
+				return filePath, []string{current}
+			}
+			if exp.Sources[i] == "*" {
+				return target, []string{current, da.relativePath(target)}
+			}
+
+			definedIn, chain := da.traceSymbol(target, exp.Sources[i], visited)
+			if definedIn == "" {
+				return filePath, []string{current}
+			}
+			return definedIn, append([]string{current}, chain...)
+		}
+	}
+
+	if name == "default" {
+		return "", nil
+	}
+
+	for _, exp := range exports {
+		if !exp.Star {
+			continue
+		}
+		target := da.resolveImportFile(filePath, exp.From)
+		if target == "" {
+			continue
+		}
+		if definedIn, chain := da.traceSymbol(target, name, visited); definedIn != "" {
+			return definedIn, append([]string{current}, chain...)
+		}
+	}
+
+	return "", nil
+}
+
+// collectExportNames возвращает все имена, видимые снаружи модуля, включая
+// имена, пришедшие через export * (кроме default).
+func (da *DependencyAnalyzer) collectExportNames(filePath string, visited map[string]bool) map[string]int {
+	names := make(map[string]int)
+	if visited[filePath] {
+		return names
+	}
+	visited[filePath] = true
+
+	for _, exp := range da.fileExports(filePath) {
+		if exp.Star {
+			target := da.resolveImportFile(filePath, exp.From)
+			if target == "" {
+				continue
+			}
+			for name := range da.collectExportNames(target, visited) {
+				if _, exists := names[name]; !exists && name != "default" {
+					names[name] = exp.Line
+				}
+			}
+			continue
+		}
+		for _, name := range exp.Names {
+			if _, exists := names[name]; !exists {
+				names[name] = exp.Line
+			}
+		}
+	}
+
+	return names
+}
+
+// longestStarChain возвращает самую длинную цепочку export * от файла.
+// Результат кэшируется по файлу, поэтому ромбовидные графы реэкспортов
+// обходятся за линейное время. visiting содержит файлы текущей цепочки:
+// цикл обрывается на повторном файле.
+func (da *DependencyAnalyzer) longestStarChain(filePath string, visiting map[string]bool) []string {
+	if chain, ok := da.starChainCache[filePath]; ok {
+		return chain
+	}
+	if visiting[filePath] {
+		return nil
+	}
+	visiting[filePath] = true
+	defer delete(visiting, filePath)
+
+	var longest []string
+	for _, exp := range da.fileExports(filePath) {
+		if !exp.Star {
+			continue
+		}
+		target := da.resolveImportFile(filePath, exp.From)
+		if target == "" {
+			continue
+		}
+		if chain := da.longestStarChain(target, visiting); len(chain) > len(longest) {
+			longest = chain
+		}
+	}
+
+	chain := append([]string{da.relativePath(filePath)}, longest...)
+	da.starChainCache[filePath] = chain
+	return chain
+}
+
+func (da *DependencyAnalyzer) registerFile(filePath, layerName, sliceName, segmentName string) {
+	da.fileLocations[da.relativePath(filePath)] = fileLocation{
+		Layer:   layerName,
+		Slice:   sliceName,
+		Segment: segmentName,
+	}
+}
+
+func (da *DependencyAnalyzer) locateFile(relPath string) fileLocation {
+	if location, ok := da.fileLocations[relPath]; ok {
+		return location
+	}
+	return locateByPath(relPath)
+}
+
+// locateByPath определяет слой, слайс и сегмент файла только по его пути.
+func locateByPath(relPath string) fileLocation {
+	parts := strings.Split(relPath, "/")
+	for i, part := range parts {
+		if !contains(model.KnownLayers, part) {
+			continue
+		}
+
+		rest := parts[i+1:]
+		if len(rest) <= 1 {
+			return fileLocation{Layer: part, Slice: part, Segment: "root"}
+		}
+
+		location := fileLocation{Layer: part, Slice: rest[0], Segment: "root"}
+		if len(rest) > 2 && contains(model.KnownSegments, rest[1]) {
+			location.Segment = rest[1]
+		}
+		return location
+	}
+
+	return fileLocation{}
+}
+
+func contains(items []string, item string) bool {
+	for _, existing := range items {
+		if existing == item {
+			return true
+		}
+	}
+	return false
+}
+
+// traceSymbolOrigins прослеживает реэкспорты до модулей, где объявлены
+// импортированные имена, и переносит на них зависимости (attributeToOrigins).
+func (da *DependencyAnalyzer) traceSymbolOrigins() {
+	traced := make([]Dependency, 0, len(da.dependencies))
+	for _, dep := range da.dependencies {
+		traced = append(traced, da.attributeToOrigins(dep)...)
+	}
+	da.dependencies = traced
+}
+
+// attributeToOrigins делит импорт по файлам, где объявлены его имена: каждая
+// часть получает слой, слайс, сегмент и файл источника, тип зависимости
+// пересчитывается, а ViaFile сохраняет barrel-файл из импорта — по нему
+// проверяется публичный API. На barrel-файле остаются имена, объявленные в
+// нем самом или не найденные, импорт пространства имен, а также имена из
+// слайса импортирующего файла: иначе импорт превратился бы в петлю.
+func (da *DependencyAnalyzer) attributeToOrigins(dep Dependency) []Dependency {
+	if dep.ToFile == "" {
+		return []Dependency{dep}
+	}
+
+	target := da.absolutePath(dep.ToFile)
+	from := slicePath(dep.FromLayer, dep.FromSlice)
+
+	barrel := dep
+	barrel.Specifiers = nil
+	barrel.Origins = nil
+	var attributed []Dependency
+	byFile := make(map[string]int)
+
+	for _, name := range dep.Specifiers {
+		if name == "*" {
+			barrel.Specifiers = append(barrel.Specifiers, name)
+			continue
+		}
+
+		definedIn, chain := da.traceSymbol(target, name, make(map[string]bool))
+		if definedIn == "" {
+			barrel.Specifiers = append(barrel.Specifiers, name)
+			continue
+		}
+
+		file := da.relativePath(definedIn)
+		location := da.locateFile(file)
+		origin := SymbolOrigin{
+			Name:    name,
+			File:    file,
+			Layer:   location.Layer,
+			Slice:   location.Slice,
+			Segment: location.Segment,
+			Chain:   chain,
+		}
+		if file == dep.ToFile || slicePath(location.Layer, location.Slice) == from {
+			barrel.Specifiers = append(barrel.Specifiers, name)
+			barrel.Origins = append(barrel.Origins, origin)
+			continue
+		}
+
+		i, ok := byFile[file]
+		if !ok {
+			moved := dep
+			moved.ToLayer = location.Layer
+			moved.ToSlice = location.Slice
+			moved.ToSegment = location.Segment
+			moved.ToFile = file
+			moved.ViaFile = dep.ToFile
+			moved.Type = da.DetermineDepType(dep.FromLayer, dep.FromSlice, location.Layer, location.Slice)
+			moved.Specifiers = nil
+			moved.Origins = nil
+			i = len(attributed)
+			byFile[file] = i
+			attributed = append(attributed, moved)
+		}
+		attributed[i].Specifiers = append(attributed[i].Specifiers, name)
+		attributed[i].Origins = append(attributed[i].Origins, origin)
+	}
+
+	if len(attributed) == 0 {
+		dep.Origins = barrel.Origins
+		return []Dependency{dep}
+	}
+	if len(barrel.Specifiers) > 0 {
+		attributed = append([]Dependency{barrel}, attributed...)
+	}
+	return attributed
+}
+
+func (da *DependencyAnalyzer) maxReexportDepth() int {
+	if da.config != nil && da.config.MaxReexportDepth > 0 {
+		return da.config.MaxReexportDepth
+	}
+	return defaultMaxReexportDepth
+}
+
+func (da *DependencyAnalyzer) GetBarrelIssues() []BarrelIssue {
+	var issues []BarrelIssue
+
+	paths := make([]string, 0, len(da.publicAPIs))
+	for path := range da.publicAPIs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	maxDepth := da.maxReexportDepth()
+	for _, path := range paths {
+		api := da.publicAPIs[path]
+		chain := da.longestStarChain(da.absolutePath(api.File), make(map[string]bool))
+		if len(chain)-1 <= maxDepth {
+			continue
+		}
+
+		line := 0
+		for _, exp := range da.fileExports(da.absolutePath(api.File)) {
+			if exp.Star && (line == 0 || exp.Line < line) {
+				line = exp.Line
+			}
+		}
+
+		location := da.locateFile(api.File)
+		issues = append(issues, BarrelIssue{
+			Kind:  BarrelIssueDeepChain,
+			Layer: location.Layer,
+			Slice: location.Slice,
+			File:  api.File,
+			Line:  line,
+			Chain: chain,
+		})
+	}
+
+	for _, dep := range da.dependencies {
+		// Реэкспорт чужого слайса — проблема barrel-файла; обычный модуль,
+		// реэкспортирующий соседа, — просто зависимость
+		if dep.ImportKind != ImportReExport || !isPublicAPIFile(path.Base(dep.FromFile)) {
+			continue
+		}
+		if slicePath(dep.FromLayer, dep.FromSlice) == slicePath(dep.ToLayer, dep.ToSlice) {
+			continue
+		}
+
+		issues = append(issues, BarrelIssue{
+			Kind:        BarrelIssueCrossSlice,
+			Layer:       dep.FromLayer,
+			Slice:       dep.FromSlice,
+			File:        dep.FromFile,
+			Line:        dep.Line,
+			TargetLayer: dep.ToLayer,
+			TargetSlice: dep.ToSlice,
+		})
+	}
+
+	return issues
+}
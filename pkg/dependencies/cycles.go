@@ -0,0 +1,48 @@
+package dependencies
+
+type SliceCycle struct {
+	Slices  []string
+	Imports []Dependency
+}
+
+// FindCycles находит циклы в графе слайсов: каждая компонента сильной
+// связности из двух и более слайсов — отдельный цикл со всеми импортами
+// между его участниками.
+func FindCycles(deps []Dependency) []SliceCycle {
+	graph := make(map[string]map[string][]Dependency)
+	for _, dep := range deps {
+		from := slicePath(dep.FromLayer, dep.FromSlice)
+		to := slicePath(dep.ToLayer, dep.ToSlice)
+		if from == to {
+			continue
+		}
+		if graph[from] == nil {
+			graph[from] = make(map[string][]Dependency)
+		}
+		graph[from][to] = append(graph[from][to], dep)
+	}
+
+	var cycles []SliceCycle
+	for _, component := range stronglyConnected(graph) {
+		if len(component) < 2 {
+			continue
+		}
+
+		members := make(map[string]bool)
+		for _, slice := range component {
+			members[slice] = true
+		}
+
+		cycle := SliceCycle{Slices: component}
+		for _, from := range component {
+			for _, to := range sortedKeys(graph[from]) {
+				if members[to] {
+					cycle.Imports = append(cycle.Imports, graph[from][to]...)
+				}
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles
+}
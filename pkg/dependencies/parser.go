@@ -0,0 +1,239 @@
+package dependencies
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/model"
+)
+
+type ImportKind = model.ImportKind
+
+const (
+	ImportStatic     = model.ImportStatic
+	ImportSideEffect = model.ImportSideEffect
+	ImportDynamic    = model.ImportDynamic
+	ImportRequire    = model.ImportRequire
+	ImportReExport   = model.ImportReExport
+)
+
+type importStatement struct {
+	Path       string
+	Line       int
+	Kind       ImportKind
+	Specifiers []string
+}
+
+// exportStatement описывает один export. Names содержит имена, под которыми
+// символы видны снаружи, Sources — соответствующие им имена в модуле From.
+type exportStatement struct {
+	Names   []string
+	Sources []string
+	From    string
+	Star    bool
+	Line    int
+}
+
+var (
+	blockCommentPattern = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	lineCommentPattern  = regexp.MustCompile(`(?m)^\s*//.*$`)
+
+	importFromPattern    = regexp.MustCompile(`\bimport\s+(?:type\s+)?([^'";]+?)\s+from\s+['"]([^'"]+)['"]`)
+	importSideEffect     = regexp.MustCompile(`\bimport\s+['"]([^'"]+)['"]`)
+	importDynamicPattern = regexp.MustCompile(`\bimport\s*\(\s*['"]([^'"]+)['"]\s*\)`)
+	requirePattern       = regexp.MustCompile(`\brequire\s*\(\s*['"]([^'"]+)['"]\s*\)`)
+
+	exportStarPattern  = regexp.MustCompile(`\bexport\s+(?:type\s+)?\*(?:\s+as\s+([\w$]+))?\s+from\s+['"]([^'"]+)['"]`)
+	exportListPattern  = regexp.MustCompile(`\bexport\s+(?:type\s+)?\{([^}]*)\}(?:\s*from\s+['"]([^'"]+)['"])?`)
+	exportDeclPattern  = regexp.MustCompile(`\bexport\s+(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(?:const|let|var|function\*?|class|interface|type|enum)\s+([\w$]+)`)
+	exportDefaultRegex = regexp.MustCompile(`\bexport\s+default\b`)
+)
+
+func stripComments(content string) string {
+	content = blockCommentPattern.ReplaceAllStringFunc(content, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
+	return lineCommentPattern.ReplaceAllString(content, "")
+}
+
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func parseImports(content string) []importStatement {
+	content = stripComments(content)
+
+	var imports []importStatement
+
+	for _, m := range importFromPattern.FindAllStringSubmatchIndex(content, -1) {
+		imports = append(imports, importStatement{
+			Path:       content[m[4]:m[5]],
+			Line:       lineAt(content, m[0]),
+			Kind:       ImportStatic,
+			Specifiers: parseImportClause(content[m[2]:m[3]]),
+		})
+	}
+
+	patterns := []struct {
+		kind    ImportKind
+		pattern *regexp.Regexp
+	}{
+		{ImportSideEffect, importSideEffect},
+		{ImportDynamic, importDynamicPattern},
+		{ImportRequire, requirePattern},
+	}
+	for _, p := range patterns {
+		for _, m := range p.pattern.FindAllStringSubmatchIndex(content, -1) {
+			imports = append(imports, importStatement{
+				Path: content[m[2]:m[3]],
+				Line: lineAt(content, m[0]),
+				Kind: p.kind,
+			})
+		}
+	}
+
+	for _, m := range exportStarPattern.FindAllStringSubmatchIndex(content, -1) {
+		imports = append(imports, importStatement{
+			Path:       content[m[4]:m[5]],
+			Line:       lineAt(content, m[0]),
+			Kind:       ImportReExport,
+			Specifiers: []string{"*"},
+		})
+	}
+
+	for _, m := range exportListPattern.FindAllStringSubmatchIndex(content, -1) {
+		if m[4] < 0 {
+			continue
+		}
+		var names []string
+		for _, spec := range parseSpecifierList(content[m[2]:m[3]]) {
+			names = append(names, spec[0])
+		}
+		imports = append(imports, importStatement{
+			Path:       content[m[4]:m[5]],
+			Line:       lineAt(content, m[0]),
+			Kind:       ImportReExport,
+			Specifiers: names,
+		})
+	}
+
+	sortImports(imports)
+
+	return imports
+}
+
+func parseExports(content string) []exportStatement {
+	content = stripComments(content)
+
+	var exports []exportStatement
+
+	for _, m := range exportStarPattern.FindAllStringSubmatchIndex(content, -1) {
+		stmt := exportStatement{
+			From: content[m[4]:m[5]],
+			Line: lineAt(content, m[0]),
+			Star: m[2] < 0,
+		}
+		if m[2] >= 0 {
+			stmt.Names = []string{content[m[2]:m[3]]}
+			stmt.Sources = []string{"*"}
+		}
+		exports = append(exports, stmt)
+	}
+
+	for _, m := range exportListPattern.FindAllStringSubmatchIndex(content, -1) {
+		stmt := exportStatement{Line: lineAt(content, m[0])}
+		if m[4] >= 0 {
+			stmt.From = content[m[4]:m[5]]
+		}
+		for _, spec := range parseSpecifierList(content[m[2]:m[3]]) {
+			stmt.Sources = append(stmt.Sources, spec[0])
+			stmt.Names = append(stmt.Names, spec[1])
+		}
+		exports = append(exports, stmt)
+	}
+
+	for _, m := range exportDeclPattern.FindAllStringSubmatchIndex(content, -1) {
+		name := content[m[2]:m[3]]
+		exports = append(exports, exportStatement{
+			Names:   []string{name},
+			Sources: []string{name},
+			Line:    lineAt(content, m[0]),
+		})
+	}
+
+	for _, m := range exportDefaultRegex.FindAllStringIndex(content, -1) {
+		exports = append(exports, exportStatement{
+			Names:   []string{"default"},
+			Sources: []string{"default"},
+			Line:    lineAt(content, m[0]),
+		})
+	}
+
+	return exports
+}
+
+// parseImportClause возвращает имена, которые импорт забирает из модуля:
+// "default" для импорта по умолчанию и "*" для импорта пространства имен.
+func parseImportClause(clause string) []string {
+	var names []string
+
+	clause = strings.TrimSpace(clause)
+	if start := strings.Index(clause, "{"); start >= 0 {
+		end := strings.LastIndex(clause, "}")
+		if end < start {
+			end = len(clause)
+		}
+		for _, spec := range parseSpecifierList(clause[start+1 : end]) {
+			names = append(names, spec[0])
+		}
+		if end < len(clause) {
+			end++
+		}
+		clause = clause[:start] + clause[end:]
+	}
+
+	for _, part := range strings.Split(clause, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+		case strings.HasPrefix(part, "*"):
+			names = append([]string{"*"}, names...)
+		default:
+			names = append([]string{"default"}, names...)
+		}
+	}
+
+	return names
+}
+
+// parseSpecifierList разбирает список вида "a, b as c, type D" и возвращает
+// пары {исходное имя, имя после переименования}.
+func parseSpecifierList(list string) [][2]string {
+	var specs [][2]string
+
+	for _, part := range strings.Split(list, ",") {
+		fields := strings.Fields(part)
+		if len(fields) > 0 && fields[0] == "type" && len(fields) != 1 && fields[1] != "as" {
+			fields = fields[1:]
+		}
+		switch len(fields) {
+		case 0:
+			continue
+		case 3:
+			if fields[1] == "as" {
+				specs = append(specs, [2]string{fields[0], fields[2]})
+				continue
+			}
+		}
+		specs = append(specs, [2]string{fields[0], fields[0]})
+	}
+
+	return specs
+}
+
+func sortImports(imports []importStatement) {
+	sort.SliceStable(imports, func(i, j int) bool {
+		return imports[i].Line < imports[j].Line
+	})
+}
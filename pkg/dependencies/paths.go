@@ -0,0 +1,204 @@
+package dependencies
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/model"
+)
+
+type PathHop struct {
+	From    string
+	To      string
+	Imports []Dependency
+}
+
+type DependencyPath []PathHop
+
+type pathGraph struct {
+	edges map[string]map[string][]Dependency
+	nodes map[string][]string
+}
+
+// PathNodeKind — вид узла в запросе путей.
+type PathNodeKind string
+
+const (
+	PathNodeSlice PathNodeKind = "slice"
+	PathNodeFile  PathNodeKind = "file"
+)
+
+// PathNode — узел запроса путей: слайс ("pages/home") или файл
+// ("pages/home/ui/HomePage.tsx").
+type PathNode struct {
+	Kind PathNodeKind
+	Path string
+}
+
+// NewPathNode определяет вид узла по структуре проекта: слайсом считается
+// путь существующего слайса, даже если он похож на имя файла
+// ("entities/user.profile"), остальные пути — файлами.
+func NewPathNode(structure *model.ProjectStructure, node string) PathNode {
+	node = strings.Trim(filepath.ToSlash(node), "/")
+	for _, layer := range structure.Layers {
+		for _, slice := range layer.Slices {
+			if slicePath(layer.Name, slice.Name) == node {
+				return PathNode{Kind: PathNodeSlice, Path: node}
+			}
+		}
+	}
+	return PathNode{Kind: PathNodeFile, Path: node}
+}
+
+func newPathGraph(deps []Dependency, fileLevel bool) *pathGraph {
+	g := &pathGraph{
+		edges: make(map[string]map[string][]Dependency),
+		nodes: make(map[string][]string),
+	}
+
+	for _, dep := range deps {
+		from := slicePath(dep.FromLayer, dep.FromSlice)
+		to := slicePath(dep.ToLayer, dep.ToSlice)
+		if fileLevel {
+			if dep.FromFile == "" || dep.ToFile == "" {
+				continue
+			}
+			g.addNode(dep.FromFile, from)
+			g.addNode(dep.ToFile, to)
+			from, to = dep.FromFile, dep.ToFile
+		}
+		if from == to {
+			continue
+		}
+
+		if g.edges[from] == nil {
+			g.edges[from] = make(map[string][]Dependency)
+		}
+		g.edges[from][to] = append(g.edges[from][to], dep)
+	}
+
+	return g
+}
+
+func (g *pathGraph) addNode(file, slice string) {
+	for _, existing := range g.nodes[slice] {
+		if existing == file {
+			return
+		}
+	}
+	g.nodes[slice] = append(g.nodes[slice], file)
+}
+
+// resolve превращает узел запроса в набор вершин графа: для файлового графа
+// слайс раскрывается во все его файлы.
+func (g *pathGraph) resolve(node PathNode, fileLevel bool) []string {
+	path := strings.Trim(filepath.ToSlash(node.Path), "/")
+	if !fileLevel || node.Kind == PathNodeFile {
+		return []string{path}
+	}
+	files := append([]string{}, g.nodes[path]...)
+	sort.Strings(files)
+	return files
+}
+
+func (g *pathGraph) successors(node string) []string {
+	next := make([]string, 0, len(g.edges[node]))
+	for to := range g.edges[node] {
+		next = append(next, to)
+	}
+	sort.Strings(next)
+	return next
+}
+
+// FindPaths возвращает простые пути от from до to в графе зависимостей,
+// отсортированные по длине. Если хотя бы один из узлов — файл, поиск ведется
+// по графу файлов. limit ограничивает число кратчайших путей, 0 означает все
+// пути.
+func FindPaths(deps []Dependency, from, to PathNode, limit int) []DependencyPath {
+	fileLevel := from.Kind == PathNodeFile || to.Kind == PathNodeFile
+	g := newPathGraph(deps, fileLevel)
+
+	targets := make(map[string]bool)
+	for _, node := range g.resolve(to, fileLevel) {
+		targets[node] = true
+	}
+
+	reverse := make(map[string][]string)
+	for source, edges := range g.edges {
+		for target := range edges {
+			reverse[target] = append(reverse[target], source)
+		}
+	}
+
+	canReach := make(map[string]bool)
+	queue := make([]string, 0, len(targets))
+	for node := range targets {
+		canReach[node] = true
+		queue = append(queue, node)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, prev := range reverse[node] {
+			if !canReach[prev] {
+				canReach[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	var found [][]string
+	partial := [][]string{}
+	for _, start := range g.resolve(from, fileLevel) {
+		if canReach[start] && !targets[start] {
+			partial = append(partial, []string{start})
+		}
+	}
+
+	for len(partial) > 0 && (limit == 0 || len(found) < limit) {
+		path := partial[0]
+		partial = partial[1:]
+
+		last := path[len(path)-1]
+		for _, next := range g.successors(last) {
+			if !canReach[next] || containsNode(path, next) {
+				continue
+			}
+
+			extended := append(append([]string{}, path...), next)
+			if targets[next] {
+				found = append(found, extended)
+				if limit > 0 && len(found) >= limit {
+					break
+				}
+				continue
+			}
+			partial = append(partial, extended)
+		}
+	}
+
+	paths := make([]DependencyPath, 0, len(found))
+	for _, nodes := range found {
+		path := make(DependencyPath, 0, len(nodes)-1)
+		for i := 0; i < len(nodes)-1; i++ {
+			path = append(path, PathHop{
+				From:    nodes[i],
+				To:      nodes[i+1],
+				Imports: g.edges[nodes[i]][nodes[i+1]],
+			})
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func containsNode(path []string, node string) bool {
+	for _, n := range path {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
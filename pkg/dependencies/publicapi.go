@@ -0,0 +1,183 @@
+package dependencies
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/model"
+)
+
+type APIIssueKind = model.APIIssueKind
+
+const (
+	APIIssueUnusedExport = model.APIIssueUnusedExport
+	APIIssueNotExported  = model.APIIssueNotExported
+)
+
+type APIIssue = model.APIIssue
+
+type publicAPI struct {
+	File    string
+	Exports map[string]int
+}
+
+func isPublicAPIFile(fileName string) bool {
+	switch fileName {
+	case "index.ts", "index.tsx", "index.js", "index.jsx":
+		return true
+	}
+	return false
+}
+
+func slicePath(layerName, sliceName string) string {
+	if sliceName == "" || sliceName == layerName {
+		return layerName
+	}
+	return layerName + "/" + sliceName
+}
+
+func (da *DependencyAnalyzer) analyzePublicAPI(filePath, layerName, sliceName string) []string {
+	if _, err := os.Stat(filePath); err != nil {
+		return nil
+	}
+
+	api := &publicAPI{
+		File:    da.relativePath(filePath),
+		Exports: da.collectExportNames(filePath, make(map[string]bool)),
+	}
+
+	da.publicAPIs[slicePath(layerName, sliceName)] = api
+
+	return api.exportNames()
+}
+
+func (api *publicAPI) exportNames() []string {
+	names := make([]string, 0, len(api.Exports))
+	for name := range api.Exports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (da *DependencyAnalyzer) GetPublicAPIIssues() []APIIssue {
+	var issues []APIIssue
+
+	used := make(map[string]map[string]bool)
+	usesAll := make(map[string]bool)
+
+	for _, dep := range da.dependencies {
+		toLayer, toSlice := importedSlice(dep)
+		from := slicePath(dep.FromLayer, dep.FromSlice)
+		to := slicePath(toLayer, toSlice)
+		if from == to {
+			continue
+		}
+
+		api, hasAPI := da.publicAPIs[to]
+
+		// Динамический импорт, require и импорт ради побочных эффектов не
+		// называют символы: слайс может использовать любой его экспорт
+		if len(dep.Specifiers) == 0 {
+			usesAll[to] = true
+			continue
+		}
+
+		for _, name := range dep.Specifiers {
+			if name == "*" {
+				usesAll[to] = true
+				continue
+			}
+
+			if used[to] == nil {
+				used[to] = make(map[string]bool)
+			}
+			used[to][name] = true
+
+			if hasAPI {
+				if _, exported := api.Exports[name]; !exported {
+					issues = append(issues, APIIssue{
+						Kind:  APIIssueNotExported,
+						Layer: toLayer,
+						Slice: toSlice,
+						Name:  name,
+						File:  dep.FromFile,
+						Line:  dep.Line,
+					})
+				}
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(da.publicAPIs))
+	for path := range da.publicAPIs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if usesAll[path] {
+			continue
+		}
+
+		api := da.publicAPIs[path]
+		layerName, sliceName := path, path
+		if i := strings.Index(path, "/"); i >= 0 {
+			layerName, sliceName = path[:i], path[i+1:]
+		}
+
+		for _, name := range api.exportNames() {
+			if used[path][name] {
+				continue
+			}
+			issues = append(issues, APIIssue{
+				Kind:  APIIssueUnusedExport,
+				Layer: layerName,
+				Slice: sliceName,
+				Name:  name,
+				File:  api.File,
+				Line:  api.Exports[name],
+			})
+		}
+	}
+
+	return issues
+}
+
+// importedSlice возвращает слой и слайс модуля, указанного в импорте: для
+// зависимости, перенесенной на источник имен, — слайс barrel-файла.
+func importedSlice(dep Dependency) (string, string) {
+	if dep.ViaFile == "" {
+		return dep.ToLayer, dep.ToSlice
+	}
+	location := locateByPath(dep.ViaFile)
+	return location.Layer, location.Slice
+}
+
+// BypassesPublicAPI сообщает, импортирует ли зависимость модуль другого
+// слайса в обход его публичного API (например, "entities/user/model/store"
+// вместо "entities/user"). Слои app и shared не делятся на слайсы, поэтому
+// для них проверка не выполняется.
+func BypassesPublicAPI(dep Dependency) bool {
+	if dep.ViaFile != "" {
+		// Зависимость перенесена на источник имен: проверяется barrel-файл,
+		// указанный в импорте
+		dep.ToLayer, dep.ToSlice = importedSlice(dep)
+		dep.ToFile = dep.ViaFile
+	}
+	if dep.ToLayer == "app" || dep.ToLayer == "shared" {
+		return false
+	}
+	to := slicePath(dep.ToLayer, dep.ToSlice)
+	if slicePath(dep.FromLayer, dep.FromSlice) == to {
+		return false
+	}
+
+	if dep.ToFile != "" {
+		dir := "/" + path.Dir(dep.ToFile)
+		return !strings.HasSuffix(dir, "/"+to) || !isPublicAPIFile(path.Base(dep.ToFile))
+	}
+	return dep.ToSegment != "" && dep.ToSegment != "root"
+}
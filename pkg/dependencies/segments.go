@@ -0,0 +1,235 @@
+package dependencies
+
+import (
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	SegmentScopeAll   = config.SegmentScopeAll
+	SegmentScopeSlice = config.SegmentScopeSlice
+	SegmentScopeCross = config.SegmentScopeCross
+)
+
+type SegmentViolation = model.SegmentViolation
+
+type SegmentCycle = model.SegmentCycle
+
+// segmentFromImportPath определяет сегмент по пути импорта, если файл не
+// удалось разрешить: "entities/user/model" — сегмент model, "entities/user"
+// — публичный API слайса (root).
+func segmentFromImportPath(importPath, layerName string) string {
+	if layerName == "" {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimLeft(importPath, "./"), "/")
+	for i, part := range parts {
+		if part != layerName {
+			continue
+		}
+		if i+2 < len(parts) && contains(model.KnownSegments, parts[i+2]) {
+			return parts[i+2]
+		}
+		return "root"
+	}
+
+	return "root"
+}
+
+func matchSegment(pattern, segment string) bool {
+	return pattern == "*" || pattern == segment
+}
+
+func ruleApplies(rule config.SegmentRule, dep Dependency) bool {
+	if !matchSegment(rule.From, dep.FromSegment) || !matchSegment(rule.To, dep.ToSegment) {
+		return false
+	}
+
+	sameSlice := slicePath(dep.FromLayer, dep.FromSlice) == slicePath(dep.ToLayer, dep.ToSlice)
+	switch rule.Scope {
+	case SegmentScopeSlice:
+		return sameSlice
+	case SegmentScopeCross:
+		return !sameSlice
+	default:
+		return true
+	}
+}
+
+// GetSegmentViolations проверяет зависимости и импорты внутри слайсов по
+// правилам segmentRules из конфигурации. Каждое правило запрещает импорт из сегмента From в сегмент To.
+func (da *DependencyAnalyzer) GetSegmentViolations() []SegmentViolation {
+	var violations []SegmentViolation
+	if da.config == nil || len(da.config.SegmentRules) == 0 {
+		return violations
+	}
+
+	for _, dep := range da.allImports() {
+		if dep.FromSegment == "" || dep.ToSegment == "" {
+			continue
+		}
+
+		for _, rule := range da.config.SegmentRules {
+			if !ruleApplies(rule, dep) {
+				continue
+			}
+			violations = append(violations, SegmentViolation{
+				Rule:        rule,
+				FromLayer:   dep.FromLayer,
+				FromSlice:   dep.FromSlice,
+				FromSegment: dep.FromSegment,
+				ToLayer:     dep.ToLayer,
+				ToSlice:     dep.ToSlice,
+				ToSegment:   dep.ToSegment,
+				File:        dep.FromFile,
+				Line:        dep.Line,
+				ImportPath:  dep.ImportPath,
+			})
+			break
+		}
+	}
+
+	return violations
+}
+
+// GetSegmentCycles находит циклы между сегментами внутри одного слайса
+// (например, model → ui → model).
+func (da *DependencyAnalyzer) GetSegmentCycles() []SegmentCycle {
+	edges := make(map[string]map[string]map[string][]Dependency)
+
+	for _, dep := range da.allImports() {
+		from := slicePath(dep.FromLayer, dep.FromSlice)
+		if from != slicePath(dep.ToLayer, dep.ToSlice) {
+			continue
+		}
+		if dep.FromSegment == "" || dep.ToSegment == "" || dep.FromSegment == dep.ToSegment {
+			continue
+		}
+
+		if edges[from] == nil {
+			edges[from] = make(map[string]map[string][]Dependency)
+		}
+		if edges[from][dep.FromSegment] == nil {
+			edges[from][dep.FromSegment] = make(map[string][]Dependency)
+		}
+		edges[from][dep.FromSegment][dep.ToSegment] = append(edges[from][dep.FromSegment][dep.ToSegment], dep)
+	}
+
+	slices := make([]string, 0, len(edges))
+	for slice := range edges {
+		slices = append(slices, slice)
+	}
+	sort.Strings(slices)
+
+	var cycles []SegmentCycle
+	for _, slice := range slices {
+		graph := edges[slice]
+		for _, component := range stronglyConnected(graph) {
+			if len(component) < 2 {
+				continue
+			}
+
+			members := make(map[string]bool)
+			for _, segment := range component {
+				members[segment] = true
+			}
+
+			cycle := SegmentCycle{Segments: component}
+			for _, from := range component {
+				for _, to := range sortedKeys(graph[from]) {
+					if members[to] {
+						cycle.Imports = append(cycle.Imports, graph[from][to]...)
+					}
+				}
+			}
+			cycle.Layer, cycle.Slice = cycle.Imports[0].FromLayer, cycle.Imports[0].FromSlice
+			cycles = append(cycles, cycle)
+		}
+	}
+
+	return cycles
+}
+
+func sortedKeys(m map[string][]Dependency) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stronglyConnected возвращает компоненты сильной связности графа
+// (алгоритм Тарьяна), узлы внутри компоненты отсортированы.
+func stronglyConnected(graph map[string]map[string][]Dependency) [][]string {
+	nodes := make(map[string]bool)
+	for from, targets := range graph {
+		nodes[from] = true
+		for to := range targets {
+			nodes[to] = true
+		}
+	}
+	sortedNodes := make([]string, 0, len(nodes))
+	for node := range nodes {
+		sortedNodes = append(sortedNodes, node)
+	}
+	sort.Strings(sortedNodes)
+
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var components [][]string
+
+	var visit func(node string)
+	visit = func(node string) {
+		indices[node] = index
+		lowlink[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, next := range sortedKeys(graph[node]) {
+			if _, seen := indices[next]; !seen {
+				visit(next)
+				if lowlink[next] < lowlink[node] {
+					lowlink[node] = lowlink[next]
+				}
+			} else if onStack[next] && indices[next] < lowlink[node] {
+				lowlink[node] = indices[next]
+			}
+		}
+
+		if lowlink[node] == indices[node] {
+			var component []string
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == node {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range sortedNodes {
+		if _, seen := indices[node]; !seen {
+			visit(node)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+
+	return components
+}
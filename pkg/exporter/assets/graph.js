@@ -0,0 +1,453 @@
+// graph.js — минимальная библиотека для графа зависимостей в HTML-отчете:
+// силовая раскладка, масштабирование, панорамирование и перетаскивание узлов
+// в SVG без внешних зависимостей, чтобы отчет работал без доступа к сети.
+var FSDGraph = (function () {
+    'use strict';
+
+    var SVG_NS = 'http://www.w3.org/2000/svg';
+
+    function svgElement(name, attrs, parent) {
+        var el = document.createElementNS(SVG_NS, name);
+        Object.keys(attrs || {}).forEach(function (key) {
+            el.setAttribute(key, attrs[key]);
+        });
+        if (parent) parent.appendChild(el);
+        return el;
+    }
+
+    function constant(value) {
+        return typeof value === 'function' ? value : function () { return value; };
+    }
+
+    // Силовая симуляция по мотивам d3-force: пружины ребер, отталкивание
+    // узлов, притяжение к центру и предотвращение наложений.
+    function Simulation(nodes, links, options) {
+        this.nodes = nodes;
+        this.links = links;
+        this.options = options;
+        this.alpha = 1;
+        this.alphaMin = 0.001;
+        this.alphaDecay = 1 - Math.pow(0.001, 1 / 300);
+        this.alphaTarget = 0;
+        this.velocityDecay = 0.4;
+        this.listeners = [];
+        this.frame = null;
+
+        var radius = 10 * Math.sqrt(0.5);
+        var angle = Math.PI * (3 - Math.sqrt(5));
+        nodes.forEach(function (node, i) {
+            if (node.x === undefined || node.y === undefined) {
+                var r = radius * Math.sqrt(0.5 + i);
+                var a = i * angle;
+                node.x = options.width / 2 + r * Math.cos(a);
+                node.y = options.height / 2 + r * Math.sin(a);
+            }
+            node.vx = node.vx || 0;
+            node.vy = node.vy || 0;
+        });
+
+        var degree = {};
+        links.forEach(function (link) {
+            degree[link.source.id] = (degree[link.source.id] || 0) + 1;
+            degree[link.target.id] = (degree[link.target.id] || 0) + 1;
+        });
+        links.forEach(function (link) {
+            var s = degree[link.source.id], t = degree[link.target.id];
+            link.bias = s / (s + t);
+        });
+    }
+
+    Simulation.prototype.on = function (listener) {
+        this.listeners.push(listener);
+        return this;
+    };
+
+    Simulation.prototype.tick = function () {
+        var opts = this.options, alpha = this.alpha, nodes = this.nodes;
+        var i, j, a, b, dx, dy, l, w;
+
+        this.links.forEach(function (link) {
+            a = link.source;
+            b = link.target;
+            dx = (b.x + b.vx) - (a.x + a.vx) || 1e-6;
+            dy = (b.y + b.vy) - (a.y + a.vy) || 1e-6;
+            l = Math.sqrt(dx * dx + dy * dy);
+            l = (l - opts.linkDistance) / l * alpha * opts.linkStrength;
+            dx *= l;
+            dy *= l;
+            b.vx -= dx * link.bias;
+            b.vy -= dy * link.bias;
+            a.vx += dx * (1 - link.bias);
+            a.vy += dy * (1 - link.bias);
+        });
+
+        var maxDistance2 = opts.chargeDistanceMax * opts.chargeDistanceMax;
+        var collide2 = 4 * opts.collideRadius * opts.collideRadius;
+        for (i = 0; i < nodes.length; i++) {
+            a = nodes[i];
+            for (j = i + 1; j < nodes.length; j++) {
+                b = nodes[j];
+                dx = b.x - a.x || (Math.random() - 0.5) * 1e-6;
+                dy = b.y - a.y || (Math.random() - 0.5) * 1e-6;
+                l = dx * dx + dy * dy;
+                if (l < maxDistance2) {
+                    w = opts.charge * alpha / Math.max(l, 1);
+                    a.vx += dx * w;
+                    a.vy += dy * w;
+                    b.vx -= dx * w;
+                    b.vy -= dy * w;
+                }
+                if (l < collide2) {
+                    var d = Math.sqrt(l);
+                    var overlap = (2 * opts.collideRadius - d) / d * 0.5;
+                    a.vx -= dx * overlap * 0.5;
+                    a.vy -= dy * overlap * 0.5;
+                    b.vx += dx * overlap * 0.5;
+                    b.vy += dy * overlap * 0.5;
+                }
+            }
+        }
+
+        var cx = opts.width / 2, cy = opts.height / 2;
+        var sx = 0, sy = 0;
+        nodes.forEach(function (node) {
+            node.vx += (cx - node.x) * opts.gravity * alpha;
+            node.vy += (cy - node.y) * opts.gravity * alpha;
+        });
+
+        var velocityDecay = this.velocityDecay;
+        nodes.forEach(function (node) {
+            if (node.fx != null) {
+                node.x = node.fx;
+                node.vx = 0;
+            } else {
+                node.vx *= 1 - velocityDecay;
+                node.x += node.vx;
+            }
+            if (node.fy != null) {
+                node.y = node.fy;
+                node.vy = 0;
+            } else {
+                node.vy *= 1 - velocityDecay;
+                node.y += node.vy;
+            }
+            sx += node.x;
+            sy += node.y;
+        });
+
+        if (nodes.length) {
+            sx = sx / nodes.length - cx;
+            sy = sy / nodes.length - cy;
+            nodes.forEach(function (node) {
+                if (node.fx == null) node.x -= sx;
+                if (node.fy == null) node.y -= sy;
+            });
+        }
+    };
+
+    Simulation.prototype.step = function () {
+        var self = this;
+        this.frame = null;
+        for (var n = 0; n < 3 && this.alpha >= this.alphaMin; n++) {
+            this.alpha += (this.alphaTarget - this.alpha) * this.alphaDecay;
+            this.tick();
+        }
+        this.listeners.forEach(function (listener) { listener(); });
+        if (this.alpha >= this.alphaMin) {
+            this.frame = window.requestAnimationFrame(function () { self.step(); });
+        }
+    };
+
+    Simulation.prototype.restart = function (alpha) {
+        var self = this;
+        if (alpha !== undefined) this.alpha = alpha;
+        if (this.frame === null) {
+            this.frame = window.requestAnimationFrame(function () { self.step(); });
+        }
+        return this;
+    };
+
+    Simulation.prototype.stop = function () {
+        if (this.frame !== null) {
+            window.cancelAnimationFrame(this.frame);
+            this.frame = null;
+        }
+        return this;
+    };
+
+    // create рисует граф в контейнере и возвращает объект управления.
+    // При layout: 'force' положение узлов рассчитывает симуляция, иначе
+    // используются заданные x и y, а ребра проходят через link.points.
+    function create(container, options) {
+        var opts = Object.assign({
+            width: container.clientWidth || 800,
+            height: 600,
+            layout: 'force',
+            radius: 15,
+            linkDistance: 100,
+            linkStrength: 0.5,
+            charge: -300,
+            chargeDistanceMax: 500,
+            collideRadius: 50,
+            gravity: 0.05,
+            minScale: 0.1,
+            maxScale: 4,
+            bands: [],
+            bandWidth: 0,
+            markers: {}
+        }, options);
+
+        var nodeColor = constant(opts.nodeColor || '#34495e');
+        var nodeOpacity = constant(opts.nodeOpacity || 1);
+        var nodeLabel = constant(opts.nodeLabel || function (d) { return d.id; });
+        var nodeClass = constant(opts.nodeClass || 'node');
+        var linkColor = constant(opts.linkColor || '#999');
+        var linkOpacity = constant(opts.linkOpacity || 1);
+        var linkClass = constant(opts.linkClass || 'link');
+        var linkMarker = constant(opts.linkMarker || null);
+
+        var byId = {};
+        opts.nodes.forEach(function (node) { byId[node.id] = node; });
+        var links = opts.links.filter(function (link) {
+            var source = typeof link.source === 'object' ? link.source.id : link.source;
+            var target = typeof link.target === 'object' ? link.target.id : link.target;
+            if (!byId[source] || !byId[target]) return false;
+            link.source = byId[source];
+            link.target = byId[target];
+            return true;
+        });
+
+        var svg = svgElement('svg', {
+            width: opts.width,
+            height: opts.height,
+            viewBox: [0, 0, opts.width, opts.height].join(' ')
+        }, container);
+
+        var defs = svgElement('defs', {}, svg);
+        Object.keys(opts.markers).forEach(function (name) {
+            var marker = svgElement('marker', {
+                id: 'arrow-' + name,
+                viewBox: '0 -5 10 10',
+                refX: 25,
+                refY: 0,
+                markerWidth: 6,
+                markerHeight: 6,
+                orient: 'auto'
+            }, defs);
+            svgElement('path', { d: 'M0,-5L10,0L0,5', fill: opts.markers[name] }, marker);
+        });
+
+        var root = svgElement('g', {}, svg);
+
+        // Полосы слоев рисуются только в фиксированной раскладке
+        if (opts.layout !== 'force') {
+            var bandsGroup = svgElement('g', { 'class': 'bands' }, root);
+            (opts.bands || []).forEach(function (band, i) {
+                var group = svgElement('g', { 'class': 'band band-' + (i % 2 ? 'odd' : 'even') }, bandsGroup);
+                svgElement('rect', { x: 0, y: band.y, width: opts.bandWidth, height: band.height }, group);
+                var label = svgElement('text', { 'class': 'band-label', x: 8, y: band.y + 18 }, group);
+                label.textContent = band.label;
+            });
+        }
+
+        var linksGroup = svgElement('g', { 'class': 'links' }, root);
+        var nodesGroup = svgElement('g', { 'class': 'nodes' }, root);
+
+        var transform = { x: 0, y: 0, k: 1 };
+        function applyTransform() {
+            root.setAttribute('transform', 'translate(' + transform.x + ',' + transform.y + ') scale(' + transform.k + ')');
+        }
+
+        function svgPoint(event) {
+            var rect = svg.getBoundingClientRect();
+            return {
+                x: (event.clientX - rect.left) * opts.width / (rect.width || opts.width),
+                y: (event.clientY - rect.top) * opts.height / (rect.height || opts.height)
+            };
+        }
+
+        function zoomAt(point, factor) {
+            var k = Math.min(opts.maxScale, Math.max(opts.minScale, transform.k * factor));
+            transform.x = point.x - (point.x - transform.x) * k / transform.k;
+            transform.y = point.y - (point.y - transform.y) * k / transform.k;
+            transform.k = k;
+            applyTransform();
+        }
+
+        links.forEach(function (link) {
+            var line = svgElement('path', {
+                'class': linkClass(link),
+                fill: 'none',
+                stroke: linkColor(link),
+                'stroke-opacity': linkOpacity(link)
+            }, linksGroup);
+            var marker = linkMarker(link);
+            if (marker) line.setAttribute('marker-end', 'url(#arrow-' + marker + ')');
+            link.element = line;
+        });
+
+        // linkPath строит путь ребра: в силовой раскладке — отрезок, в
+        // фиксированной — ломаную через промежуточные точки; ребра внутри
+        // полосы и ребра вверх без промежуточных точек выгибаются дугой.
+        function linkPath(link) {
+            var s = link.source, t = link.target;
+            if (opts.layout === 'force') {
+                return 'M' + s.x + ',' + s.y + 'L' + t.x + ',' + t.y;
+            }
+
+            var points = [[s.x, s.y]].concat(link.points || [], [[t.x, t.y]]);
+            if (points.length > 2 || !(link.upward || s.y === t.y)) {
+                return 'M' + points.map(function (p) { return p[0] + ',' + p[1]; }).join('L');
+            }
+
+            var dx = t.x - s.x, dy = t.y - s.y;
+            var length = Math.sqrt(dx * dx + dy * dy) || 1;
+            var bend = s.y === t.y ? 0.3 * length : 0.4 * length;
+            var cx = (s.x + t.x) / 2 + dy / length * bend;
+            var cy = (s.y + t.y) / 2 - dx / length * bend;
+            return 'M' + s.x + ',' + s.y + 'Q' + cx + ',' + cy + ' ' + t.x + ',' + t.y;
+        }
+
+        var simulation = new Simulation(opts.nodes, links, opts);
+        var dragging = null;
+
+        var nodeElements = opts.nodes.map(function (node) {
+            var group = svgElement('g', { 'class': nodeClass(node) }, nodesGroup);
+            svgElement('circle', { r: opts.radius, fill: nodeColor(node), opacity: nodeOpacity(node) }, group);
+            var label = svgElement('text', { 'class': 'node-label', dx: 12, dy: '.35em' }, group);
+            label.textContent = nodeLabel(node);
+
+            group.addEventListener('mouseover', function (event) {
+                if (opts.onNodeOver) opts.onNodeOver(event, node);
+            });
+            group.addEventListener('mouseout', function (event) {
+                if (opts.onNodeOut) opts.onNodeOut(event, node);
+            });
+            group.addEventListener('click', function (event) {
+                if (opts.onNodeClick) opts.onNodeClick(event, node);
+            });
+            group.addEventListener('pointerdown', function (event) {
+                event.stopPropagation();
+                dragging = { node: node, moved: false };
+                node.fx = node.x;
+                node.fy = node.y;
+                if (opts.layout === 'force') {
+                    simulation.alphaTarget = 0.3;
+                    simulation.restart();
+                }
+            });
+            node.element = group;
+            return group;
+        });
+
+        function render() {
+            links.forEach(function (link) {
+                link.element.setAttribute('d', linkPath(link));
+            });
+            opts.nodes.forEach(function (node) {
+                node.element.setAttribute('transform', 'translate(' + node.x + ',' + node.y + ')');
+            });
+        }
+        simulation.on(render);
+
+        var panning = null;
+        svg.addEventListener('pointerdown', function (event) {
+            panning = { x: event.clientX, y: event.clientY, tx: transform.x, ty: transform.y };
+        });
+        function onPointerMove(event) {
+            if (dragging) {
+                var point = svgPoint(event);
+                dragging.node.fx = (point.x - transform.x) / transform.k;
+                dragging.node.fy = (point.y - transform.y) / transform.k;
+                dragging.moved = true;
+                if (opts.layout !== 'force') {
+                    dragging.node.x = dragging.node.fx;
+                    dragging.node.y = dragging.node.fy;
+                    render();
+                }
+            } else if (panning) {
+                var rect = svg.getBoundingClientRect();
+                var scale = opts.width / (rect.width || opts.width);
+                transform.x = panning.tx + (event.clientX - panning.x) * scale;
+                transform.y = panning.ty + (event.clientY - panning.y) * scale;
+                applyTransform();
+            }
+        }
+        function onPointerUp() {
+            if (dragging && opts.layout === 'force') {
+                simulation.alphaTarget = 0;
+                dragging.node.fx = null;
+                dragging.node.fy = null;
+            }
+            dragging = null;
+            panning = null;
+        }
+        window.addEventListener('pointermove', onPointerMove);
+        window.addEventListener('pointerup', onPointerUp);
+        svg.addEventListener('wheel', function (event) {
+            event.preventDefault();
+            zoomAt(svgPoint(event), Math.pow(2, -event.deltaY * (event.deltaMode ? 0.05 : 0.002)));
+        }, { passive: false });
+
+        // fit масштабирует граф так, чтобы он целиком помещался в область
+        function fit() {
+            if (!opts.nodes.length) return;
+            var minX = Infinity, minY = Infinity, maxX = -Infinity, maxY = -Infinity;
+            opts.nodes.forEach(function (node) {
+                minX = Math.min(minX, node.x);
+                minY = Math.min(minY, node.y);
+                maxX = Math.max(maxX, node.x);
+                maxY = Math.max(maxY, node.y);
+            });
+            if (opts.layout !== 'force' && opts.bands && opts.bands.length) {
+                minX = Math.min(minX, 0);
+                maxX = Math.max(maxX, opts.bandWidth);
+                minY = Math.min(minY, opts.bands[0].y);
+                var last = opts.bands[opts.bands.length - 1];
+                maxY = Math.max(maxY, last.y + last.height);
+            }
+            var padding = 40;
+            var k = Math.min(opts.maxScale, 1,
+                opts.width / (maxX - minX + 2 * padding),
+                opts.height / (maxY - minY + 2 * padding));
+            k = Math.max(opts.minScale, k);
+            transform = {
+                x: (opts.width - (maxX + minX) * k) / 2,
+                y: (opts.height - (maxY + minY) * k) / 2,
+                k: k
+            };
+            applyTransform();
+        }
+
+        if (opts.layout === 'force') {
+            simulation.restart(1);
+        } else {
+            render();
+            fit();
+        }
+
+        return {
+            svg: svg,
+            nodes: opts.nodes,
+            links: links,
+            simulation: simulation,
+            render: render,
+            fit: fit,
+            zoomBy: function (factor) {
+                zoomAt({ x: opts.width / 2, y: opts.height / 2 }, factor);
+            },
+            resetZoom: function () {
+                transform = { x: 0, y: 0, k: 1 };
+                applyTransform();
+            },
+            destroy: function () {
+                simulation.stop();
+                window.removeEventListener('pointermove', onPointerMove);
+                window.removeEventListener('pointerup', onPointerUp);
+                container.removeChild(svg);
+            }
+        };
+    }
+
+    return { create: create, Simulation: Simulation };
+})();
@@ -0,0 +1,107 @@
+// history.js — линейный график динамики метрик по коммитам для HTML-отчета,
+// без внешних зависимостей. Точки идут слева направо от старых коммитов к
+// новым, подсказка точки описывает коммит.
+var FSDTrend = (function () {
+    'use strict';
+
+    var SVG_NS = 'http://www.w3.org/2000/svg';
+    var MARGIN = { top: 16, right: 24, bottom: 40, left: 48 };
+    var TICKS = 5;
+
+    function svgElement(name, attrs, parent) {
+        var element = document.createElementNS(SVG_NS, name);
+        Object.keys(attrs || {}).forEach(function (key) {
+            element.setAttribute(key, attrs[key]);
+        });
+        if (parent) parent.appendChild(element);
+        return element;
+    }
+
+    // niceStep подбирает шаг делений оси: 1, 2 или 5, умноженные на степень 10.
+    function niceStep(max) {
+        var raw = Math.max(max, 1) / TICKS;
+        var power = Math.pow(10, Math.floor(Math.log(raw) / Math.LN10));
+        var step = [1, 2, 5, 10].map(function (k) { return k * power; }).filter(function (k) { return k >= raw; })[0];
+        return Math.max(1, step);
+    }
+
+    // create рисует график в контейнере. Опции: entries — записи истории,
+    // series — линии { name, color, value(entry) }, label(entry) — подпись
+    // коммита на оси, title(entry) — подсказка точки, t(message) — перевод
+    // подписей.
+    function create(container, opts) {
+        opts = Object.assign({ height: 280, t: function (message) { return message; } }, opts);
+        var width = opts.width || container.clientWidth || 960;
+        var height = opts.height;
+        var svg = svgElement('svg', { width: width, height: height, 'class': 'history-svg' });
+        container.appendChild(svg);
+
+        var entries = opts.entries || [];
+        if (!entries.length) {
+            var empty = svgElement('text', { x: width / 2, y: height / 2, 'text-anchor': 'middle', 'class': 'history-label' }, svg);
+            empty.textContent = opts.t('Нет данных');
+            return { svg: svg, destroy: function () { container.removeChild(svg); } };
+        }
+
+        var plotWidth = width - MARGIN.left - MARGIN.right;
+        var plotHeight = height - MARGIN.top - MARGIN.bottom;
+        var max = 0;
+        opts.series.forEach(function (series) {
+            entries.forEach(function (entry) { max = Math.max(max, series.value(entry)); });
+        });
+        var step = niceStep(max);
+        var top = Math.ceil(Math.max(max, 1) / step) * step;
+
+        function x(index) {
+            if (entries.length === 1) return MARGIN.left + plotWidth / 2;
+            return MARGIN.left + plotWidth * index / (entries.length - 1);
+        }
+        function y(value) {
+            return MARGIN.top + plotHeight * (1 - value / top);
+        }
+
+        var axis = svgElement('g', { 'class': 'history-axis' }, svg);
+        for (var tick = 0; tick <= top; tick += step) {
+            svgElement('line', { x1: MARGIN.left, x2: width - MARGIN.right, y1: y(tick), y2: y(tick), 'class': 'history-grid' }, axis);
+            var tickLabel = svgElement('text', { x: MARGIN.left - 6, y: y(tick), dy: '.35em', 'text-anchor': 'end', 'class': 'history-label' }, axis);
+            tickLabel.textContent = tick;
+        }
+
+        // Подписей коммитов не больше, чем помещается по ширине
+        var every = Math.max(1, Math.ceil(entries.length * 70 / plotWidth));
+        entries.forEach(function (entry, i) {
+            if (i % every !== 0 && i !== entries.length - 1) return;
+            var label = svgElement('text', { x: x(i), y: height - MARGIN.bottom + 18, 'text-anchor': 'middle', 'class': 'history-label' }, axis);
+            label.textContent = opts.label ? opts.label(entry) : String(i + 1);
+        });
+
+        opts.series.forEach(function (series) {
+            var group = svgElement('g', { 'class': 'history-series' }, svg);
+            var points = entries.map(function (entry, i) {
+                return x(i).toFixed(1) + ',' + y(series.value(entry)).toFixed(1);
+            });
+            svgElement('polyline', { points: points.join(' '), fill: 'none', stroke: series.color, 'stroke-width': 2 }, group);
+            entries.forEach(function (entry, i) {
+                var point = svgElement('circle', { cx: x(i), cy: y(series.value(entry)), r: 4, fill: series.color, 'class': 'history-point' }, group);
+                var title = svgElement('title', {}, point);
+                title.textContent = series.name + ': ' + series.value(entry) + (opts.title ? '\n' + opts.title(entry) : '');
+            });
+        });
+
+        var legend = svgElement('g', { 'class': 'history-legend' }, svg);
+        var offset = MARGIN.left;
+        opts.series.forEach(function (series) {
+            svgElement('rect', { x: offset, y: height - 14, width: 10, height: 10, fill: series.color }, legend);
+            var name = svgElement('text', { x: offset + 14, y: height - 5, 'class': 'history-label' }, legend);
+            name.textContent = series.name;
+            offset += 24 + series.name.length * 7;
+        });
+
+        return {
+            svg: svg,
+            destroy: function () { container.removeChild(svg); }
+        };
+    }
+
+    return { create: create, niceStep: niceStep };
+})();
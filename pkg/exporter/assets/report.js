@@ -0,0 +1,906 @@
+// report.js — интерактивная часть HTML-отчета: treemap объема кода, граф
+// зависимостей слайсов с фильтрами, панель «Почему один слайс зависит от
+// другого?», матрица зависимостей, график динамики по коммитам и предпросмотр
+// исходного кода мест импорта.
+// Данные отчета передаются в window.FSD_REPORT.
+(function () {
+    'use strict';
+
+    var LAYER_COLORS = {
+        app: '#3498db',
+        processes: '#9b59b6',
+        pages: '#2ecc71',
+        widgets: '#f1c40f',
+        features: '#e67e22',
+        entities: '#e74c3c',
+        shared: '#95a5a6'
+    };
+
+    var DEPENDENCY_COLORS = {
+        normal: '#28a745',
+        same: '#ffc107',
+        cyclical: '#dc3545',
+        test: '#6c757d',
+        'allowed-cyclical': '#28a745'
+    };
+
+    // t переводит подпись на локаль отчета по window.FSD_REPORT.messages и
+    // подставляет аргументы вместо {0}, {1}...
+    function t(message) {
+        var messages = (window.FSD_REPORT && window.FSD_REPORT.messages) || {};
+        var args = Array.prototype.slice.call(arguments, 1);
+        return (messages[message] || message).replace(/\{(\d+)\}/g, function (match, index) {
+            return index < args.length ? args[index] : match;
+        });
+    }
+
+    function sliceId(layer, slice) {
+        return !slice || slice === layer ? layer : layer + '/' + slice;
+    }
+
+    // nodeKinds определяет вид узлов по зависимостям отчета, а не по имени:
+    // у слайса тоже может быть точка в имени (entities/user.profile).
+    function nodeKinds(dependencies) {
+        var kinds = new Map();
+        dependencies.forEach(function (d) {
+            if (d.fromFile) kinds.set(d.fromFile, 'file');
+            if (d.toFile) kinds.set(d.toFile, 'file');
+        });
+        dependencies.forEach(function (d) {
+            kinds.set(sliceId(d.fromLayer, d.fromSlice), 'slice');
+            kinds.set(sliceId(d.toLayer, d.toSlice), 'slice');
+        });
+        return kinds;
+    }
+
+    function getNodeColor(id) {
+        return LAYER_COLORS[id.split('/')[0]] || '#34495e';
+    }
+
+    // brighter осветляет цвет #rrggbb так же, как d3.color(...).brighter(k).
+    function brighter(color, k) {
+        var factor = Math.pow(1 / 0.7, k);
+        return '#' + [1, 3, 5].map(function (i) {
+            var channel = Math.min(255, Math.round(parseInt(color.substr(i, 2), 16) * factor));
+            return ('0' + channel.toString(16)).slice(-2);
+        }).join('');
+    }
+
+    // markAllowedCyclical показывает разрешенные циклические зависимости
+    // (allowedCyclical вычисляется при построении отчета) как нормальные.
+    function markAllowedCyclical(dependencies) {
+        dependencies.forEach(function (d) {
+            if (d.allowedCyclical) {
+                d.type = 'normal';
+                d.isAllowedCyclical = true;
+            }
+        });
+    }
+
+    function isAllowedCyclical(id, allowedCyclicalPaths) {
+        return allowedCyclicalPaths.indexOf(id) >= 0 || allowedCyclicalPaths.indexOf(id.split('/')[0]) >= 0;
+    }
+
+    function displayedType(type, source, target, allowedCyclicalPaths) {
+        if (type === 'cyclical' &&
+            (isAllowedCyclical(source, allowedCyclicalPaths) || isAllowedCyclical(target, allowedCyclicalPaths))) {
+            return 'normal';
+        }
+        return type;
+    }
+
+    function linkKey(source, target, type) {
+        return source + '\u0000' + target + '\u0000' + type;
+    }
+
+    // visibleGraph применяет к послойной раскладке фильтры отчета: скрытые
+    // слои и типы зависимостей, свернутые в один узел слои, поиск по слайсу
+    // или файлу и фокус на соседях узла в пределах state.hops шагов.
+    function visibleGraph(layout, allowedCyclicalPaths, state) {
+        var nodes = [];
+        var byId = {};
+        var nodeOf = {};
+
+        layout.nodes.forEach(function (n) {
+            if (state.hide.indexOf(n.layer) >= 0) return;
+
+            if (state.collapse.indexOf(n.layer) >= 0) {
+                var id = 'layer:' + n.layer;
+                if (!byId[id]) {
+                    var band = layout.bands.filter(function (b) { return b.layer === n.layer; })[0];
+                    byId[id] = {
+                        id: id,
+                        label: n.layer,
+                        layerName: n.layer,
+                        collapsed: true,
+                        members: [],
+                        files: [],
+                        x: layout.width / 2,
+                        y: band.y + band.height / 2
+                    };
+                    nodes.push(byId[id]);
+                }
+                byId[id].members.push(n.id);
+                byId[id].files = byId[id].files.concat(n.files || []);
+                nodeOf[n.id] = id;
+                return;
+            }
+
+            byId[n.id] = {
+                id: n.id,
+                label: n.id,
+                layerName: n.layer,
+                members: [n.id],
+                files: n.files || [],
+                isAllowedCyclical: isAllowedCyclical(n.id, allowedCyclicalPaths),
+                x: n.x,
+                y: n.y
+            };
+            nodes.push(byId[n.id]);
+            nodeOf[n.id] = n.id;
+        });
+
+        var links = [];
+        var linksByKey = {};
+        layout.edges.forEach(function (e) {
+            var source = nodeOf[e.source], target = nodeOf[e.target];
+            var type = displayedType(e.type, e.source, e.target, allowedCyclicalPaths);
+            if (!source || !target || source === target || state.types.indexOf(type) >= 0) return;
+
+            var key = linkKey(source, target, type);
+            var link = linksByKey[key];
+            if (!link) {
+                link = linksByKey[key] = {
+                    source: source,
+                    target: target,
+                    type: type,
+                    isAllowedCyclical: type !== e.type,
+                    upward: e.upward,
+                    points: source === e.source && target === e.target ? e.points : [],
+                    imports: 0
+                };
+                links.push(link);
+            }
+            link.imports += e.imports;
+        });
+
+        if (state.q) {
+            var query = state.q.toLowerCase();
+            var matched = {};
+            nodes.forEach(function (node) {
+                matched[node.id] = node.label.toLowerCase().indexOf(query) >= 0 ||
+                    node.files.some(function (file) { return file.toLowerCase().indexOf(query) >= 0; });
+                node.matched = matched[node.id];
+            });
+            links = links.filter(function (link) { return matched[link.source] || matched[link.target]; });
+            nodes = keepNodes(nodes, links, matched);
+        }
+
+        if (state.focus) {
+            var focus = nodeOf[state.focus] || state.focus;
+            var distance = {};
+            if (nodes.some(function (node) { return node.id === focus; })) {
+                distance[focus] = 0;
+                var queue = [focus];
+                while (queue.length) {
+                    var current = queue.shift();
+                    if (distance[current] >= state.hops) continue;
+                    links.forEach(function (link) {
+                        var next = link.source === current ? link.target : link.target === current ? link.source : null;
+                        if (next !== null && distance[next] === undefined) {
+                            distance[next] = distance[current] + 1;
+                            queue.push(next);
+                        }
+                    });
+                }
+            }
+            nodes = nodes.filter(function (node) {
+                node.focused = node.id === focus;
+                return distance[node.id] !== undefined;
+            });
+            links = links.filter(function (link) {
+                return distance[link.source] !== undefined && distance[link.target] !== undefined;
+            });
+        }
+
+        var keys = {};
+        links.forEach(function (link) { keys[linkKey(link.source, link.target, link.type)] = true; });
+
+        return { nodes: nodes, links: links, nodeOf: nodeOf, linkKeys: keys };
+    }
+
+    // keepNodes оставляет найденные узлы и узлы на концах оставшихся ребер.
+    function keepNodes(nodes, links, matched) {
+        var linked = {};
+        links.forEach(function (link) {
+            linked[link.source] = true;
+            linked[link.target] = true;
+        });
+        return nodes.filter(function (node) { return matched[node.id] || linked[node.id]; });
+    }
+
+    function setupGraph(layout, allowedCyclicalPaths, onNodeClick) {
+        var container = document.getElementById('dependency-graph');
+
+        var tooltip = document.createElement('div');
+        tooltip.className = 'tooltip';
+        container.appendChild(tooltip);
+
+        var bands = layout.bands.map(function (b) {
+            return { label: b.layer, y: b.y, height: b.height };
+        });
+
+        var graph = null;
+
+        function show(view, mode) {
+            if (graph) graph.destroy();
+
+            if (mode === 'force') {
+                view.nodes.forEach(function (node) {
+                    delete node.x;
+                    delete node.y;
+                });
+            }
+
+            graph = FSDGraph.create(container, {
+                nodes: view.nodes,
+                links: view.links,
+                height: 600,
+                layout: mode === 'force' ? 'force' : 'fixed',
+                bands: bands,
+                bandWidth: layout.width,
+                markers: DEPENDENCY_COLORS,
+                nodeLabel: function (d) {
+                    return d.collapsed ? d.label + ' (' + d.members.length + ')' : d.label;
+                },
+                nodeClass: function (d) {
+                    return 'node' + (d.collapsed ? ' node-collapsed' : '') +
+                        (d.matched ? ' node-match' : '') + (d.focused ? ' node-focus' : '');
+                },
+                nodeColor: function (d) {
+                    var color = getNodeColor(d.layerName);
+                    return d.isAllowedCyclical ? brighter(color, 0.3) : color;
+                },
+                nodeOpacity: function (d) { return d.isAllowedCyclical ? 0.8 : 1; },
+                linkClass: function (d) {
+                    return 'link link-' + d.type + (d.upward ? ' link-upward' : '');
+                },
+                linkColor: function (d) {
+                    return d.isAllowedCyclical ? DEPENDENCY_COLORS.normal : (DEPENDENCY_COLORS[d.type] || DEPENDENCY_COLORS.normal);
+                },
+                linkOpacity: function (d) { return d.isAllowedCyclical ? 0.7 : 1; },
+                linkMarker: function (d) { return d.isAllowedCyclical ? 'allowed-cyclical' : d.type; },
+                onNodeOver: function (event, d) {
+                    tooltip.innerHTML = '';
+                    var title = document.createElement('strong');
+                    title.textContent = d.label;
+                    tooltip.appendChild(title);
+                    if (d.collapsed) {
+                        tooltip.appendChild(document.createTextNode(t(' (свернутый слой, слайсов: {0})', d.members.length)));
+                    } else if (d.isAllowedCyclical) {
+                        tooltip.appendChild(document.createTextNode(t(' (разрешены циклические зависимости)')));
+                    }
+                    var rect = container.getBoundingClientRect();
+                    tooltip.style.opacity = 1;
+                    tooltip.style.left = (event.clientX - rect.left + 10) + 'px';
+                    tooltip.style.top = (event.clientY - rect.top - 30) + 'px';
+                },
+                onNodeOut: function () {
+                    tooltip.style.opacity = 0;
+                },
+                onNodeClick: function (event, d) {
+                    if (onNodeClick) onNodeClick(d);
+                }
+            });
+
+            ['layered', 'force'].forEach(function (name) {
+                var button = document.getElementById('layout-' + name);
+                if (button) button.classList.toggle('active', name === mode);
+            });
+        }
+
+        document.getElementById('zoom-in').addEventListener('click', function () {
+            graph.zoomBy(1.3);
+        });
+        document.getElementById('zoom-out').addEventListener('click', function () {
+            graph.zoomBy(1 / 1.3);
+        });
+
+        return { show: show };
+    }
+
+    // Состояние фильтров хранится в hash адреса страницы, например
+    // #q=user&hide=shared&types=test&collapse=entities&focus=pages/home&hops=2,
+    // поэтому отфильтрованный вид можно передать ссылкой.
+    function splitList(value) {
+        return value ? value.split(',').filter(Boolean) : [];
+    }
+
+    function readState(defaultLayout) {
+        var params = new URLSearchParams(window.location.hash.slice(1));
+        return {
+            q: params.get('q') || '',
+            hide: splitList(params.get('hide')),
+            types: splitList(params.get('types')),
+            collapse: splitList(params.get('collapse')),
+            focus: params.get('focus') || '',
+            hops: Math.max(1, parseInt(params.get('hops'), 10) || 1),
+            layout: params.get('layout') || defaultLayout
+        };
+    }
+
+    function writeState(state, defaultLayout) {
+        var params = new URLSearchParams();
+        if (state.q) params.set('q', state.q);
+        if (state.hide.length) params.set('hide', state.hide.join(','));
+        if (state.types.length) params.set('types', state.types.join(','));
+        if (state.collapse.length) params.set('collapse', state.collapse.join(','));
+        if (state.focus) {
+            params.set('focus', state.focus);
+            params.set('hops', state.hops);
+        }
+        if (state.layout !== defaultLayout) params.set('layout', state.layout);
+
+        var hash = params.toString();
+        if (hash !== window.location.hash.slice(1)) {
+            window.history.replaceState(null, '', hash ? '#' + hash : window.location.pathname + window.location.search);
+        }
+    }
+
+    function toggleValue(list, value, enabled) {
+        var rest = list.filter(function (item) { return item !== value; });
+        return enabled ? rest.concat([value]) : rest;
+    }
+
+    // setupFilters связывает панель фильтров с графом и списком зависимостей.
+    function setupFilters(report, allowedCyclicalPaths) {
+        var layout = report.layout;
+        var defaultLayout = report.layoutMode === 'force' ? 'force' : 'layered';
+        var state = readState(defaultLayout);
+        var graph = null;
+
+        var panel = document.getElementById('report-filters');
+        var search = document.getElementById('filter-search');
+        var focus = document.getElementById('filter-focus');
+        var hops = document.getElementById('filter-hops');
+        var layers = document.getElementById('filter-layers');
+        var items = Array.prototype.slice.call(document.querySelectorAll('.dependency-item[data-from-layer]'));
+        var counter = document.getElementById('dependency-count');
+
+        function apply() {
+            writeState(state, defaultLayout);
+            if (panel) syncControls();
+
+            var view = visibleGraph(layout, allowedCyclicalPaths, state);
+            if (graph) graph.show(view, state.layout);
+
+            var shown = 0;
+            items.forEach(function (item) {
+                var source = sliceId(item.getAttribute('data-from-layer'), item.getAttribute('data-from-slice'));
+                var target = sliceId(item.getAttribute('data-to-layer'), item.getAttribute('data-to-slice'));
+                var key = linkKey(view.nodeOf[source], view.nodeOf[target], item.getAttribute('data-type'));
+                item.hidden = !view.linkKeys[key];
+                if (!item.hidden) shown++;
+            });
+            if (counter) counter.textContent = t('показано {0} из {1}', shown, items.length);
+        }
+
+        function syncControls() {
+            search.value = state.q;
+            focus.value = state.focus;
+            hops.value = state.hops;
+            Array.prototype.forEach.call(panel.querySelectorAll('[data-filter-type]'), function (input) {
+                input.checked = state.types.indexOf(input.getAttribute('data-filter-type')) < 0;
+            });
+            Array.prototype.forEach.call(layers.querySelectorAll('[data-filter-layer]'), function (input) {
+                input.checked = state.hide.indexOf(input.getAttribute('data-filter-layer')) < 0;
+            });
+            Array.prototype.forEach.call(layers.querySelectorAll('[data-collapse-layer]'), function (button) {
+                var collapsed = state.collapse.indexOf(button.getAttribute('data-collapse-layer')) >= 0;
+                button.textContent = t(collapsed ? 'развернуть' : 'свернуть');
+                button.classList.toggle('active', collapsed);
+            });
+        }
+
+        function setupControls() {
+            layout.bands.forEach(function (band) {
+                var group = document.createElement('span');
+                group.className = 'filter-layer';
+                var label = document.createElement('label');
+                var input = document.createElement('input');
+                input.type = 'checkbox';
+                input.setAttribute('data-filter-layer', band.layer);
+                input.addEventListener('change', function () {
+                    state.hide = toggleValue(state.hide, band.layer, !input.checked);
+                    apply();
+                });
+                label.appendChild(input);
+                label.appendChild(document.createTextNode(' ' + band.layer));
+                group.appendChild(label);
+
+                var collapse = document.createElement('button');
+                collapse.type = 'button';
+                collapse.setAttribute('data-collapse-layer', band.layer);
+                collapse.addEventListener('click', function () {
+                    state.collapse = toggleValue(state.collapse, band.layer, state.collapse.indexOf(band.layer) < 0);
+                    apply();
+                });
+                group.appendChild(collapse);
+                layers.appendChild(group);
+            });
+
+            Array.prototype.forEach.call(panel.querySelectorAll('[data-filter-type]'), function (input) {
+                input.addEventListener('change', function () {
+                    state.types = toggleValue(state.types, input.getAttribute('data-filter-type'), !input.checked);
+                    apply();
+                });
+            });
+            search.addEventListener('input', function () {
+                state.q = search.value.trim();
+                apply();
+            });
+            focus.addEventListener('change', function () {
+                state.focus = focus.value.trim();
+                apply();
+            });
+            hops.addEventListener('change', function () {
+                state.hops = Math.max(1, parseInt(hops.value, 10) || 1);
+                apply();
+            });
+            document.getElementById('filter-reset').addEventListener('click', function () {
+                state = { q: '', hide: [], types: [], collapse: [], focus: '', hops: 1, layout: state.layout };
+                apply();
+            });
+        }
+
+        ['layered', 'force'].forEach(function (name) {
+            var button = document.getElementById('layout-' + name);
+            if (button) {
+                button.addEventListener('click', function () {
+                    state.layout = name;
+                    apply();
+                });
+            }
+        });
+        window.addEventListener('hashchange', function () {
+            state = readState(defaultLayout);
+            apply();
+        });
+
+        if (panel) setupControls();
+
+        if (document.getElementById('dependency-graph')) {
+            graph = setupGraph(layout, allowedCyclicalPaths, function (node) {
+                state.focus = node.collapsed ? '' : node.id;
+                if (node.collapsed) {
+                    state.collapse = toggleValue(state.collapse, node.layerName, false);
+                }
+                apply();
+            });
+        }
+        apply();
+    }
+
+    function findPaths(dependencies, from, to, limit) {
+        var kinds = nodeKinds(dependencies);
+        function isFileNode(node) {
+            return kinds.get(node) === 'file';
+        }
+        var fileLevel = isFileNode(from) || isFileNode(to);
+        var edges = new Map();
+        var sliceFiles = new Map();
+
+        dependencies.forEach(function (d) {
+            var source = sliceId(d.fromLayer, d.fromSlice);
+            var target = sliceId(d.toLayer, d.toSlice);
+            if (fileLevel) {
+                if (!d.fromFile || !d.toFile) return;
+                [[source, d.fromFile], [target, d.toFile]].forEach(function (pair) {
+                    if (!sliceFiles.has(pair[0])) sliceFiles.set(pair[0], new Set());
+                    sliceFiles.get(pair[0]).add(pair[1]);
+                });
+                source = d.fromFile;
+                target = d.toFile;
+            }
+            if (source === target) return;
+            if (!edges.has(source)) edges.set(source, new Map());
+            var targetsOf = edges.get(source);
+            if (!targetsOf.has(target)) targetsOf.set(target, []);
+            targetsOf.get(target).push(d);
+        });
+
+        function resolve(node) {
+            return (!fileLevel || isFileNode(node)) ? [node] : Array.from(sliceFiles.get(node) || []).sort();
+        }
+
+        var targets = new Set(resolve(to));
+        var reverse = new Map();
+        edges.forEach(function (targetsOf, source) {
+            targetsOf.forEach(function (_, target) {
+                if (!reverse.has(target)) reverse.set(target, []);
+                reverse.get(target).push(source);
+            });
+        });
+
+        var canReach = new Set(targets);
+        var queue = Array.from(targets);
+        while (queue.length) {
+            (reverse.get(queue.shift()) || []).forEach(function (prev) {
+                if (!canReach.has(prev)) {
+                    canReach.add(prev);
+                    queue.push(prev);
+                }
+            });
+        }
+
+        var found = [];
+        var partial = resolve(from).filter(function (n) {
+            return canReach.has(n) && !targets.has(n);
+        }).map(function (n) { return [n]; });
+
+        while (partial.length && (!limit || found.length < limit)) {
+            var path = partial.shift();
+            var next = Array.from((edges.get(path[path.length - 1]) || new Map()).keys()).sort();
+            for (var i = 0; i < next.length; i++) {
+                var node = next[i];
+                if (!canReach.has(node) || path.indexOf(node) >= 0) continue;
+                var extended = path.concat([node]);
+                if (targets.has(node)) {
+                    found.push(extended);
+                    if (limit && found.length >= limit) break;
+                    continue;
+                }
+                partial.push(extended);
+            }
+        }
+
+        return found.map(function (nodes) {
+            return nodes.slice(1).map(function (node, i) {
+                return { from: nodes[i], to: node, imports: edges.get(nodes[i]).get(node) };
+            });
+        });
+    }
+
+    function setupWhyPanel(dependencies) {
+        var datalist = document.getElementById('why-nodes');
+        var options = new Set();
+        dependencies.forEach(function (d) {
+            options.add(sliceId(d.fromLayer, d.fromSlice));
+            options.add(sliceId(d.toLayer, d.toSlice));
+            if (d.fromFile) options.add(d.fromFile);
+            if (d.toFile) options.add(d.toFile);
+        });
+        Array.from(options).sort().forEach(function (value) {
+            var option = document.createElement('option');
+            option.value = value;
+            datalist.appendChild(option);
+        });
+
+        document.getElementById('why-run').addEventListener('click', function () {
+            var from = document.getElementById('why-from').value.trim();
+            var to = document.getElementById('why-to').value.trim();
+            // Пустое поле — 10 путей, 0 — все пути
+            var limit = parseInt(document.getElementById('why-limit').value, 10);
+            if (isNaN(limit) || limit < 0) limit = 10;
+            var result = document.getElementById('why-result');
+            result.innerHTML = '';
+
+            var paths = findPaths(dependencies, from, to, limit);
+            var summary = document.createElement('p');
+            summary.textContent = paths.length ?
+                t('{0} → {1}: найдено путей: {2}', from, to, paths.length) :
+                t('{0} не зависит от {1}', from, to);
+            result.appendChild(summary);
+
+            paths.forEach(function (path, i) {
+                var block = document.createElement('div');
+                block.className = 'why-path';
+                var title = document.createElement('div');
+                title.className = 'why-path-title';
+                title.textContent = (i + 1) + '. ' + [path[0].from].concat(path.map(function (hop) { return hop.to; })).join(' → ');
+                block.appendChild(title);
+
+                path.forEach(function (hop) {
+                    var hopTitle = document.createElement('div');
+                    hopTitle.className = 'why-hop';
+                    hopTitle.textContent = hop.from + ' → ' + hop.to;
+                    block.appendChild(hopTitle);
+                    hop.imports.forEach(function (d) {
+                        var site = document.createElement('div');
+                        site.className = 'why-import';
+                        site.textContent = d.fromFile + ':' + d.line + '  ' + d.importPath;
+                        setSource(site, d.fromFile, d.line);
+                        block.appendChild(site);
+                    });
+                });
+                result.appendChild(block);
+            });
+        });
+    }
+
+    // setupDSM показывает места импорта для выбранной клетки матрицы.
+    function setupDSM(dependencies) {
+        var sites = document.getElementById('dsm-sites');
+        var selected = null;
+
+        document.getElementById('dsm').addEventListener('click', function (event) {
+            var cell = event.target.closest('td[data-from]');
+            if (!cell) return;
+
+            if (selected) selected.classList.remove('dsm-selected');
+            selected = cell;
+            cell.classList.add('dsm-selected');
+
+            var from = cell.getAttribute('data-from');
+            var to = cell.getAttribute('data-to');
+            var imports = dependencies.filter(function (d) {
+                return d.source === from && d.target === to;
+            });
+
+            sites.innerHTML = '';
+            var title = document.createElement('div');
+            title.className = 'why-path-title';
+            title.textContent = t('{0} → {1}: импортов: {2}', from, to, imports.length);
+            sites.appendChild(title);
+            imports.forEach(function (d) {
+                var site = document.createElement('div');
+                site.className = 'why-import';
+                site.textContent = d.fromFile + ':' + d.line + '  ' + d.importPath;
+                setSource(site, d.fromFile, d.line);
+                sites.appendChild(site);
+            });
+        });
+    }
+
+    function mixColor(from, to, t) {
+        return '#' + [1, 3, 5].map(function (i) {
+            var a = parseInt(from.substr(i, 2), 16), b = parseInt(to.substr(i, 2), 16);
+            return ('0' + Math.round(a + (b - a) * t).toString(16)).slice(-2);
+        }).join('');
+    }
+
+    var TREEMAP_DEPTH = { layer: 0, slice: 0.4, segment: 0.8, file: 1.2 };
+
+    function treemapTitle(node) {
+        var lines = [node.id.replace(/^[a-z]+:/, '') || node.name,
+            t('строк: {0}, байт: {1}, файлов: {2}', node.lines, node.bytes, node.files),
+            t('нарушений: {0}', node.violations)];
+        if (node.kind !== 'layer' && node.kind !== 'root') {
+            lines.push(t('нестабильность слайса: {0}', node.instability));
+        }
+        return lines.join('\n');
+    }
+
+    // setupTreemap показывает объем кода по слоям, слайсам, сегментам и
+    // файлам; размер задается числом строк, байт или файлов, цвет — слоем,
+    // числом нарушений или нестабильностью слайса.
+    function setupTreemap(tree) {
+        var container = document.getElementById('structure-treemap');
+        var view = document.getElementById('treemap-view');
+        var size = document.getElementById('treemap-size');
+        var color = document.getElementById('treemap-color');
+        var breadcrumbs = document.getElementById('treemap-path');
+
+        var maxViolations = {};
+        (function walk(node) {
+            maxViolations[node.kind] = Math.max(maxViolations[node.kind] || 0, node.violations);
+            (node.children || []).forEach(walk);
+        })(tree);
+
+        var colors = {
+            layer: function (node) {
+                return brighter(getNodeColor(node.layer || ''), TREEMAP_DEPTH[node.kind] || 0);
+            },
+            violations: function (node) {
+                if (!node.violations) return '#e8f5e9';
+                return mixColor('#f5b7b1', '#c0392b', node.violations / (maxViolations[node.kind] || 1));
+            },
+            instability: function (node) {
+                if (node.kind === 'layer') return '#bdc3c7';
+                return mixColor('#2ecc71', '#e74c3c', node.instability);
+            }
+        };
+
+        var graph = null;
+        var zoomPath = [];
+
+        function renderPath(path) {
+            zoomPath = path;
+            breadcrumbs.innerHTML = '';
+            path.forEach(function (node, i) {
+                var crumb = document.createElement('button');
+                crumb.type = 'button';
+                crumb.textContent = node.name;
+                crumb.disabled = i === path.length - 1;
+                crumb.addEventListener('click', function () { graph.zoomTo(i); });
+                breadcrumbs.appendChild(crumb);
+            });
+        }
+
+        function show() {
+            var restore = zoomPath.length;
+            if (graph) graph.destroy();
+            graph = FSDTreemap.create(container, {
+                root: tree,
+                view: view.value,
+                value: function (node) { return node[size.value]; },
+                color: colors[color.value] || colors.layer,
+                title: treemapTitle,
+                t: t,
+                onZoom: renderPath
+            });
+
+            // После смены вида или метрики остаемся на том же уровне
+            zoomPath.slice(1, restore).forEach(function (node) {
+                if (node[size.value] > 0) graph.zoomIn(node);
+            });
+            renderPath(graph.path());
+        }
+
+        [view, size, color].forEach(function (select) {
+            select.addEventListener('change', show);
+        });
+        show();
+    }
+
+    var HISTORY_RULE_COLORS = {
+        'fsd/upward-import': '#e67e22',
+        'fsd/cross-slice-import': '#9b59b6',
+        'fsd/public-api-bypass': '#3498db',
+        'fsd/cycle': '#e74c3c'
+    };
+
+    function historyTitle(entry) {
+        return [entry.hash.slice(0, 8) + ' ' + entry.date.slice(0, 10), entry.subject, entry.author].join('\n');
+    }
+
+    // setupHistory показывает динамику метрик по коммитам из fsd_history.json:
+    // нарушения — всего и по правилам, остальные метрики — одной линией.
+    function setupHistory(history) {
+        var container = document.getElementById('history-chart');
+        var metric = document.getElementById('history-metric');
+        var chart = null;
+
+        function series() {
+            if (metric.value !== 'violations') {
+                return [{
+                    name: metric.options[metric.selectedIndex].text,
+                    color: '#4a69bd',
+                    value: function (entry) { return entry.totals[metric.value] || 0; }
+                }];
+            }
+            var rules = Object.keys(HISTORY_RULE_COLORS).filter(function (rule) {
+                return history.some(function (entry) { return (entry.rules || {})[rule]; });
+            });
+            return [{
+                name: t('всего нарушений'),
+                color: '#2c3e50',
+                value: function (entry) { return entry.totals.violations; }
+            }].concat(rules.map(function (rule) {
+                return {
+                    name: rule,
+                    color: HISTORY_RULE_COLORS[rule],
+                    value: function (entry) { return (entry.rules || {})[rule] || 0; }
+                };
+            }));
+        }
+
+        function show() {
+            if (chart) chart.destroy();
+            chart = FSDTrend.create(container, {
+                entries: history,
+                series: series(),
+                label: function (entry) { return entry.hash.slice(0, 7); },
+                title: historyTitle,
+                t: t
+            });
+        }
+
+        metric.addEventListener('change', show);
+        show();
+    }
+
+    function setSource(element, file, line) {
+        if (!file) return;
+        element.setAttribute('data-source-file', file);
+        element.setAttribute('data-source-line', line);
+    }
+
+    // loadSource берет исходный код из встроенных в отчет файлов, а если
+    // файл не встроен — запрашивает его у локального сервера отчета.
+    function loadSource(report, file) {
+        var sources = report.sources || {};
+        if (Object.prototype.hasOwnProperty.call(sources, file)) {
+            return Promise.resolve(sources[file]);
+        }
+        if (!report.sourceURL || !/^https?:$/.test(window.location.protocol)) {
+            return Promise.reject(new Error(t('файл не встроен в отчет (см. html.sourceMaxBytes)')));
+        }
+        var url = report.sourceURL + file.split('/').map(encodeURIComponent).join('/');
+        return fetch(url).then(function (response) {
+            if (!response.ok) throw new Error(t('сервер вернул {0}', response.status));
+            return response.text();
+        });
+    }
+
+    // setupSourcePreview открывает файл с подсвеченной строкой импорта по
+    // клику на любой элемент с атрибутом data-source-file.
+    function setupSourcePreview(report) {
+        var panel = document.getElementById('source-preview');
+        var title = document.getElementById('source-preview-title');
+        var code = document.getElementById('source-preview-code');
+        var requested = null;
+
+        function show(file, line) {
+            requested = line ? file + ':' + line : file;
+            title.textContent = requested;
+            code.innerHTML = '';
+            panel.hidden = false;
+
+            var current = requested;
+            loadSource(report, file).then(function (content) {
+                if (current !== requested) return;
+                var highlighted = null;
+                content.split(/\r?\n/).forEach(function (text, i) {
+                    var row = document.createElement('div');
+                    row.className = 'source-line';
+                    var number = document.createElement('span');
+                    number.className = 'source-line-number';
+                    number.textContent = i + 1;
+                    row.appendChild(number);
+                    row.appendChild(document.createTextNode(text));
+                    if (i + 1 === line) {
+                        row.classList.add('source-line-highlight');
+                        highlighted = row;
+                    }
+                    code.appendChild(row);
+                });
+                if (highlighted && highlighted.scrollIntoView) {
+                    highlighted.scrollIntoView({ block: 'center' });
+                }
+            }, function (error) {
+                if (current !== requested) return;
+                code.textContent = t('Исходный код недоступен: {0}', error.message);
+            });
+        }
+
+        document.addEventListener('click', function (event) {
+            var element = event.target.closest && event.target.closest('[data-source-file]');
+            if (!element || !element.getAttribute('data-source-file')) return;
+            show(element.getAttribute('data-source-file'), parseInt(element.getAttribute('data-source-line'), 10) || 0);
+        });
+        document.getElementById('source-preview-close').addEventListener('click', function () {
+            panel.hidden = true;
+            requested = null;
+        });
+    }
+
+    document.addEventListener('DOMContentLoaded', function () {
+        var report = window.FSD_REPORT;
+        if (!report) return;
+
+        var dependencies = report.dependencies || [];
+        var allowedCyclicalPaths = report.allowedCyclicalDependencies || [];
+
+        markAllowedCyclical(dependencies);
+        if (report.layout && (document.getElementById('report-filters') || document.getElementById('dependency-graph'))) {
+            setupFilters(report, allowedCyclicalPaths);
+        }
+        if (document.getElementById('why-run')) {
+            // Импорты внутри слайсов нужны для путей между файлами
+            setupWhyPanel(dependencies.concat(report.internalImports || []));
+        }
+        if (document.getElementById('dsm')) {
+            setupDSM(dependencies);
+        }
+        if (report.tree && document.getElementById('structure-treemap')) {
+            setupTreemap(report.tree);
+        }
+        if (report.history && report.history.length && document.getElementById('history-chart')) {
+            setupHistory(report.history);
+        }
+        if (document.getElementById('source-preview')) {
+            setupSourcePreview(report);
+        }
+    });
+})();
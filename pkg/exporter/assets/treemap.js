@@ -0,0 +1,229 @@
+// treemap.js — treemap и sunburst иерархии слой → слайс → сегмент → файл
+// для HTML-отчета, без внешних зависимостей. Узел, на который кликнули,
+// становится корнем (zoom), onZoom получает путь от корня до него.
+var FSDTreemap = (function () {
+    'use strict';
+
+    var SVG_NS = 'http://www.w3.org/2000/svg';
+    var HEADER = 18;
+    var PADDING = 2;
+    var RINGS = 3;
+
+    function svgElement(name, attrs, parent) {
+        var element = document.createElementNS(SVG_NS, name);
+        Object.keys(attrs || {}).forEach(function (key) {
+            element.setAttribute(key, attrs[key]);
+        });
+        if (parent) parent.appendChild(element);
+        return element;
+    }
+
+    function worst(row, sum, side) {
+        var max = 0, min = Infinity;
+        row.forEach(function (item) {
+            max = Math.max(max, item.area);
+            min = Math.min(min, item.area);
+        });
+        var s2 = sum * sum, side2 = side * side;
+        return Math.max(side2 * max / s2, s2 / (side2 * min));
+    }
+
+    // squarify раскладывает элементы с весом value в прямоугольнике так,
+    // чтобы стороны получившихся прямоугольников были близки к квадрату.
+    function squarify(items, x, y, w, h) {
+        var total = items.reduce(function (sum, item) { return sum + item.value; }, 0);
+        if (!total || w <= 0 || h <= 0) return [];
+
+        var scale = w * h / total;
+        var queue = items.slice().sort(function (a, b) { return b.value - a.value; }).map(function (item) {
+            return { item: item, area: item.value * scale };
+        });
+        var result = [];
+
+        while (queue.length) {
+            var side = Math.min(w, h);
+            var row = [queue.shift()];
+            var sum = row[0].area;
+            while (queue.length) {
+                var next = queue[0];
+                if (worst(row.concat([next]), sum + next.area, side) > worst(row, sum, side)) break;
+                row.push(queue.shift());
+                sum += next.area;
+            }
+
+            var thickness = sum / side;
+            var offset = 0;
+            row.forEach(function (cell) {
+                var length = cell.area / thickness;
+                if (w >= h) {
+                    result.push({ item: cell.item, x: x, y: y + offset, w: thickness, h: length });
+                } else {
+                    result.push({ item: cell.item, x: x + offset, y: y, w: length, h: thickness });
+                }
+                offset += length;
+            });
+            if (w >= h) {
+                x += thickness;
+                w -= thickness;
+            } else {
+                y += thickness;
+                h -= thickness;
+            }
+        }
+        return result;
+    }
+
+    function arcPath(x0, x1, r0, r1) {
+        if (x1 - x0 >= 2 * Math.PI - 1e-6) x1 = x0 + 2 * Math.PI - 1e-6;
+        var large = x1 - x0 > Math.PI ? 1 : 0;
+        function point(angle, r) {
+            return (r * Math.sin(angle)).toFixed(2) + ',' + (-r * Math.cos(angle)).toFixed(2);
+        }
+        return 'M' + point(x0, r1) +
+            'A' + r1 + ',' + r1 + ' 0 ' + large + ' 1 ' + point(x1, r1) +
+            'L' + point(x1, r0) +
+            'A' + r0 + ',' + r0 + ' 0 ' + large + ' 0 ' + point(x0, r0) + 'Z';
+    }
+
+    // create рисует иерархию opts.root в контейнере. Опции: view ('treemap'
+    // или 'sunburst'), value(node) — вес узла, color(node) — цвет,
+    // title(node) — подсказка, t(message) — перевод подписей, onZoom(path) —
+    // смена корня.
+    function create(container, opts) {
+        opts = Object.assign({ view: 'treemap', height: 500, t: function (message) { return message; } }, opts);
+        var width = opts.width || container.clientWidth || 960;
+        var height = opts.height;
+        var svg = svgElement('svg', { width: width, height: height, 'class': 'treemap-svg' });
+        container.appendChild(svg);
+
+        var path = [opts.root];
+
+        function visible(node) {
+            return (node.children || []).filter(function (child) { return opts.value(child) > 0; });
+        }
+
+        function zoom(node, index) {
+            if (index !== undefined) {
+                path = path.slice(0, index + 1);
+            } else if (visible(node).length) {
+                path.push(node);
+            } else {
+                return;
+            }
+            render();
+            if (opts.onZoom) opts.onZoom(path.slice());
+        }
+
+        function cell(node, parent) {
+            var group = svgElement('g', { 'class': 'treemap-node treemap-' + node.kind }, parent);
+            if (node.kind === 'file') {
+                group.setAttribute('data-source-file', node.id.replace(/^file:/, ''));
+                group.setAttribute('data-source-line', 0);
+            }
+            var title = svgElement('title', {}, group);
+            title.textContent = opts.title ? opts.title(node) : node.name;
+            // Клик по файлу не приближает родителя и доходит до документа,
+            // где открывается предпросмотр исходного кода
+            group.addEventListener('click', function (event) {
+                if (event.treemapHandled) return;
+                event.treemapHandled = true;
+                if (node.kind !== 'file') zoom(node);
+            });
+            return group;
+        }
+
+        function label(group, text, x, y, w) {
+            if (w < 30) return;
+            var element = svgElement('text', { 'class': 'treemap-label', x: x + 4, y: y + 13 }, group);
+            var maxChars = Math.floor((w - 8) / 7);
+            element.textContent = text.length > maxChars ? text.slice(0, Math.max(1, maxChars - 1)) + '…' : text;
+        }
+
+        function renderTreemap(root) {
+            squarify(visible(root).map(function (node) {
+                return { node: node, value: opts.value(node) };
+            }), 0, 0, width, height).forEach(function (r) {
+                var node = r.item.node;
+                var group = cell(node, svg);
+                svgElement('rect', {
+                    x: r.x, y: r.y, width: Math.max(0, r.w - 1), height: Math.max(0, r.h - 1),
+                    fill: opts.color(node)
+                }, group);
+                label(group, node.name, r.x, r.y, r.w);
+
+                // Второй уровень показывает содержимое узла внутри его прямоугольника
+                var inner = visible(node);
+                if (!inner.length || r.h < HEADER * 2 || r.w < 20) return;
+                squarify(inner.map(function (child) {
+                    return { node: child, value: opts.value(child) };
+                }), r.x + PADDING, r.y + HEADER, r.w - 2 * PADDING - 1, r.h - HEADER - PADDING - 1).forEach(function (c) {
+                    var childGroup = cell(c.item.node, group);
+                    svgElement('rect', {
+                        x: c.x, y: c.y, width: Math.max(0, c.w - 1), height: Math.max(0, c.h - 1),
+                        fill: opts.color(c.item.node), 'class': 'treemap-inner'
+                    }, childGroup);
+                    if (c.h > 16) label(childGroup, c.item.node.name, c.x, c.y, c.w);
+                });
+            });
+        }
+
+        function renderSunburst(root) {
+            var radius = Math.min(width, height) / 2 - 4;
+            var ring = radius / (RINGS + 1);
+            var group = svgElement('g', { transform: 'translate(' + width / 2 + ',' + height / 2 + ')' }, svg);
+
+            var center = svgElement('circle', { r: ring, 'class': 'sunburst-center' }, group);
+            var centerTitle = svgElement('title', {}, center);
+            centerTitle.textContent = path.length > 1 ? opts.t('на уровень выше') : (opts.title ? opts.title(root) : root.name);
+            center.addEventListener('click', function () {
+                if (path.length > 1) zoom(path[path.length - 2], path.length - 2);
+            });
+            var centerLabel = svgElement('text', { 'class': 'sunburst-label', 'text-anchor': 'middle', dy: '.35em' }, group);
+            centerLabel.textContent = root.name;
+
+            function arcs(node, x0, x1, depth) {
+                var total = opts.value(node);
+                var angle = x0;
+                visible(node).forEach(function (child) {
+                    var span = (x1 - x0) * opts.value(child) / total;
+                    var childGroup = cell(child, group);
+                    svgElement('path', {
+                        d: arcPath(angle, angle + span, ring * depth, ring * (depth + 1) - 1),
+                        fill: opts.color(child)
+                    }, childGroup);
+                    if (depth < RINGS) arcs(child, angle, angle + span, depth + 1);
+                    angle += span;
+                });
+            }
+            arcs(root, 0, 2 * Math.PI, 1);
+        }
+
+        function render() {
+            while (svg.firstChild) svg.removeChild(svg.firstChild);
+            var root = path[path.length - 1];
+            if (!opts.value(root)) {
+                var empty = svgElement('text', { x: width / 2, y: height / 2, 'text-anchor': 'middle', 'class': 'treemap-label' }, svg);
+                empty.textContent = opts.t('Нет данных');
+                return;
+            }
+            if (opts.view === 'sunburst') {
+                renderSunburst(root);
+            } else {
+                renderTreemap(root);
+            }
+        }
+
+        render();
+
+        return {
+            svg: svg,
+            render: render,
+            zoomTo: function (index) { zoom(path[index], index); },
+            zoomIn: function (node) { zoom(node); },
+            path: function () { return path.slice(); },
+            destroy: function () { container.removeChild(svg); }
+        };
+    }
+
+    return { create: create, squarify: squarify };
+})();
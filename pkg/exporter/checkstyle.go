@@ -0,0 +1,78 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/model"
+)
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+type checkstyleExporter struct{}
+
+func init() {
+	Register(checkstyleExporter{})
+}
+
+func (checkstyleExporter) Name() string {
+	return "checkstyle"
+}
+
+func (checkstyleExporter) FileName() string {
+	return "fsd_structure.checkstyle.xml"
+}
+
+func (checkstyleExporter) Export(w io.Writer, report *Report) error {
+	return WriteCheckstyle(w, report.Structure, report.Config)
+}
+
+// WriteCheckstyle записывает нарушения в формате Checkstyle: ошибки
+// группируются по импортирующим файлам и указывают строку импорта.
+func WriteCheckstyle(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	byFile := make(map[string][]checkstyleError)
+	for _, violation := range CollectViolations(structure) {
+		file := sourcePath(cfg, violation.File)
+		byFile[file] = append(byFile[file], checkstyleError{
+			Line:     violation.Line,
+			Severity: ruleLevel(violation.RuleID),
+			Message:  violation.ReportMessage,
+			Source:   violation.RuleID,
+		})
+	}
+
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	report := checkstyleReport{Version: "4.3"}
+	for _, file := range files {
+		errors := byFile[file]
+		sort.SliceStable(errors, func(i, j int) bool {
+			return errors[i].Line < errors[j].Line
+		})
+		report.Files = append(report.Files, checkstyleFile{Name: file, Errors: errors})
+	}
+
+	return writeXML(w, report)
+}
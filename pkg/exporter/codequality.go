@@ -0,0 +1,121 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/model"
+)
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+var codeQualitySeverities = map[string]string{
+	"error":   "major",
+	"warning": "minor",
+	"note":    "info",
+}
+
+type codeQualityExporter struct{}
+
+func init() {
+	Register(codeQualityExporter{})
+}
+
+func (codeQualityExporter) Name() string {
+	return "codequality"
+}
+
+func (codeQualityExporter) FileName() string {
+	return "fsd_structure.codequality.json"
+}
+
+func (codeQualityExporter) Export(w io.Writer, report *Report) error {
+	return WriteCodeQuality(w, report.Structure, report.Config)
+}
+
+// violationFingerprint строит отпечаток нарушения, который не меняется при
+// сдвиге строк: правило, файл, путь импорта и пара слайсов. Повторяющиеся
+// импорты в одном файле различаются порядковым номером.
+func violationFingerprint(violation Violation, occurrence int) string {
+	dep := violation.Dependency
+	key := strings.Join([]string{
+		violation.RuleID,
+		violation.File,
+		dep.ImportPath,
+		sliceID(dep.FromLayer, dep.FromSlice),
+		sliceID(dep.ToLayer, dep.ToSlice),
+		fmt.Sprint(occurrence),
+	}, "\x00")
+
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// violationFingerprints возвращает отпечатки нарушений в том же порядке;
+// отпечатки уникальны в пределах отчета.
+func violationFingerprints(violations []Violation) []string {
+	fingerprints := make([]string, len(violations))
+	seen := make(map[string]int)
+
+	for i, violation := range violations {
+		fingerprint := violationFingerprint(violation, 0)
+		occurrence := seen[fingerprint]
+		seen[fingerprint]++
+		if occurrence > 0 {
+			fingerprint = violationFingerprint(violation, occurrence)
+		}
+		fingerprints[i] = fingerprint
+	}
+
+	return fingerprints
+}
+
+// WriteCodeQuality записывает нарушения в формате отчета GitLab Code Quality.
+func WriteCodeQuality(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	issues := []codeQualityIssue{}
+	violations := CollectViolations(structure)
+	fingerprints := violationFingerprints(violations)
+
+	for i, violation := range violations {
+		line := violation.Line
+		if line == 0 {
+			line = 1
+		}
+
+		issues = append(issues, codeQualityIssue{
+			Description: violation.ReportMessage,
+			CheckName:   violation.RuleID,
+			Fingerprint: fingerprints[i],
+			Severity:    codeQualitySeverities[ruleLevel(violation.RuleID)],
+			Location: codeQualityLocation{
+				Path:  sourcePath(cfg, violation.File),
+				Lines: codeQualityLines{Begin: line},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,119 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+type cytoscapeDocument struct {
+	Data     cytoscapeGraphData `json:"data"`
+	Elements cytoscapeElements  `json:"elements"`
+}
+
+type cytoscapeGraphData struct {
+	Name string `json:"name"`
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeNode `json:"nodes"`
+	Edges []cytoscapeEdge `json:"edges"`
+}
+
+type cytoscapeNode struct {
+	Data    cytoscapeNodeData `json:"data"`
+	Classes string            `json:"classes"`
+}
+
+type cytoscapeNodeData struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Kind   string `json:"kind"`
+	Path   string `json:"path"`
+	Parent string `json:"parent,omitempty"`
+}
+
+type cytoscapeEdge struct {
+	Data    cytoscapeEdgeData `json:"data"`
+	Classes string            `json:"classes"`
+}
+
+type cytoscapeEdgeData struct {
+	ID         string `json:"id"`
+	Source     string `json:"source"`
+	Target     string `json:"target"`
+	Type       string `json:"type"`
+	ImportKind string `json:"importKind"`
+	Weight     int    `json:"weight"`
+}
+
+type cytoscapeExporter struct{}
+
+func init() {
+	Register(cytoscapeExporter{})
+}
+
+func (cytoscapeExporter) Name() string {
+	return "cytoscape"
+}
+
+func (cytoscapeExporter) FileName() string {
+	return "fsd_structure.cyjs"
+}
+
+func (cytoscapeExporter) Export(w io.Writer, report *Report) error {
+	return writeCytoscape(w, buildHierarchyGraph(report))
+}
+
+// WriteCytoscape записывает граф зависимостей в формате Cytoscape JSON
+// (Cytoscape.js и Cytoscape Desktop). Иерархия передается составными
+// узлами через поле parent.
+func WriteCytoscape(w io.Writer, structure *model.ProjectStructure, fileLevel bool) error {
+	return writeCytoscape(w, buildHierarchyGraph(graphReport(structure, fileLevel)))
+}
+
+func writeCytoscape(w io.Writer, g *hierarchyGraph) error {
+	doc := cytoscapeDocument{
+		Data: cytoscapeGraphData{Name: toolName},
+		Elements: cytoscapeElements{
+			Nodes: []cytoscapeNode{},
+			Edges: []cytoscapeEdge{},
+		},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Elements.Nodes = append(doc.Elements.Nodes, cytoscapeNode{
+			Data: cytoscapeNodeData{
+				ID:     node.ID,
+				Label:  node.Name,
+				Kind:   node.Kind,
+				Path:   node.Path,
+				Parent: node.Parent,
+			},
+			Classes: node.Kind,
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Elements.Edges = append(doc.Elements.Edges, cytoscapeEdge{
+			Data: cytoscapeEdgeData{
+				ID:         edge.ID,
+				Source:     edge.Source,
+				Target:     edge.Target,
+				Type:       edge.Type,
+				ImportKind: edge.ImportKind,
+				Weight:     edge.Weight,
+			},
+			Classes: edge.Type,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return i18n.Errorf("ошибка при кодировании в JSON: %v", err)
+	}
+	return nil
+}
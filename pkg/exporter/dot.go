@@ -0,0 +1,127 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/model"
+)
+
+var dependencyColors = map[dependencies.DependencyType]string{
+	dependencies.DependencyNormal:    "#28a745",
+	dependencies.DependencySameLayer: "#ffc107",
+	dependencies.DependencyCyclical:  "#dc3545",
+	dependencies.DependencyTest:      "#6c757d",
+}
+
+func dependencyColor(depType dependencies.DependencyType) string {
+	if color, ok := dependencyColors[depType]; ok {
+		return color
+	}
+	return dependencyColors[dependencies.DependencyNormal]
+}
+
+type dotExporter struct{}
+
+func init() {
+	Register(dotExporter{})
+}
+
+func (dotExporter) Name() string {
+	return "dot"
+}
+
+func (dotExporter) FileName() string {
+	return "fsd_structure.dot"
+}
+
+func (dotExporter) Export(w io.Writer, report *Report) error {
+	return WriteDOT(w, report.Structure, fileLevelGraph(report.Config))
+}
+
+// WriteDOT записывает граф слайсов в формате Graphviz: каждый слой — отдельный
+// кластер, слои выстраиваются сверху вниз в порядке model.KnownLayers, цвет
+// ребра соответствует типу зависимости. При fileLevel узлами графа становятся
+// файлы, сгруппированные в кластеры слайсов.
+func WriteDOT(w io.Writer, structure *model.ProjectStructure, fileLevel bool) error {
+	g := buildDependencyGraph(structure, fileLevel)
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "digraph fsd {")
+	fmt.Fprintln(out, "  rankdir=TB;")
+	fmt.Fprintln(out, "  newrank=true;")
+	fmt.Fprintln(out, "  compound=true;")
+	fmt.Fprintln(out, `  node [shape=box, style="rounded,filled", fillcolor="#ffffff", fontname="Helvetica"];`)
+	fmt.Fprintln(out, `  edge [fontname="Helvetica", fontsize=10];`)
+
+	for i, layer := range g.Layers {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  subgraph %s {\n", dotQuote("cluster_"+layer.Name))
+		fmt.Fprintf(out, "    label=%s;\n", dotQuote(layer.Name))
+		fmt.Fprintln(out, `    style="rounded,filled";`)
+		fmt.Fprintln(out, `    fillcolor="#f5f5f5";`)
+		if !fileLevel {
+			fmt.Fprintln(out, "    rank=same;")
+		}
+		fmt.Fprintf(out, "    %s [shape=point, style=invis];\n", dotQuote(layerAnchor(i)))
+
+		for _, slice := range layer.Slices {
+			if !fileLevel {
+				fmt.Fprintf(out, "    %s [label=%s];\n", dotQuote(slice.ID), dotQuote(slice.Name))
+				continue
+			}
+			if len(slice.Files) == 0 {
+				continue
+			}
+
+			fmt.Fprintf(out, "    subgraph %s {\n", dotQuote("cluster_"+slice.ID))
+			fmt.Fprintf(out, "      label=%s;\n", dotQuote(slice.Name))
+			fmt.Fprintln(out, `      fillcolor="#ffffff";`)
+			for _, file := range slice.Files {
+				fmt.Fprintf(out, "      %s [label=%s];\n", dotQuote(file), dotQuote(strings.TrimPrefix(file, slice.ID+"/")))
+			}
+			fmt.Fprintln(out, "    }")
+		}
+		fmt.Fprintln(out, "  }")
+	}
+
+	if len(g.Layers) > 1 {
+		fmt.Fprintln(out)
+		for i := 0; i < len(g.Layers)-1; i++ {
+			fmt.Fprintf(out, "  %s -> %s [style=invis];\n", dotQuote(layerAnchor(i)), dotQuote(layerAnchor(i+1)))
+		}
+	}
+
+	if len(g.Edges) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, edge := range g.Edges {
+		attrs := []string{"color=" + dotQuote(dependencyColor(edge.Type))}
+		if len(edge.Imports) > 1 {
+			attrs = append(attrs, "label="+dotQuote(strconv.Itoa(len(edge.Imports))))
+		}
+		switch edge.Type {
+		case dependencies.DependencyCyclical:
+			attrs = append(attrs, "constraint=false", "penwidth=2")
+		case dependencies.DependencyTest:
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(out, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To), strings.Join(attrs, ", "))
+	}
+
+	fmt.Fprintln(out, "}")
+
+	return out.Flush()
+}
+
+func layerAnchor(index int) string {
+	return "__layer_" + strconv.Itoa(index)
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
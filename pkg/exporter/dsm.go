@@ -0,0 +1,241 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"html/template"
+	"io"
+	"strconv"
+
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	dsmCellDiagonal = "diagonal"
+	dsmCellDownward = "downward"
+	dsmCellSame     = "same"
+	dsmCellUpward   = "upward"
+)
+
+// dsmMatrix — матрица структурных зависимостей (DSM): строки — импортирующие
+// слайсы, столбцы — импортируемые, на обеих осях слайсы идут в порядке
+// слоев. Клетки ниже диагонали — импорты из нижележащего слоя в вышележащий.
+type dsmMatrix struct {
+	Slices []dsmSlice
+	Cells  [][]dsmCell
+}
+
+type dsmSlice struct {
+	ID    string
+	Layer string
+}
+
+// dsmCell — импорты из слайса From в слайс To. Kind — положение клетки
+// относительно диагонали: downward, upward, same (внутри слоя) или diagonal.
+type dsmCell struct {
+	From    string
+	To      string
+	Kind    string
+	Imports []dependencies.Dependency
+}
+
+func (c dsmCell) Count() int {
+	return len(c.Imports)
+}
+
+func buildDSM(structure *model.ProjectStructure) *dsmMatrix {
+	g := buildDependencyGraph(structure, false)
+	matrix := &dsmMatrix{}
+
+	index := make(map[string]int)
+	ranks := make(map[string]int)
+	for rank, layer := range g.Layers {
+		for _, slice := range layer.Slices {
+			index[slice.ID] = len(matrix.Slices)
+			ranks[slice.ID] = rank
+			matrix.Slices = append(matrix.Slices, dsmSlice{ID: slice.ID, Layer: layer.Name})
+		}
+	}
+
+	for i, from := range matrix.Slices {
+		row := make([]dsmCell, len(matrix.Slices))
+		for j, to := range matrix.Slices {
+			cell := dsmCell{From: from.ID, To: to.ID}
+			switch {
+			case i == j:
+				cell.Kind = dsmCellDiagonal
+			case ranks[from.ID] == ranks[to.ID]:
+				cell.Kind = dsmCellSame
+			case i > j:
+				cell.Kind = dsmCellUpward
+			default:
+				cell.Kind = dsmCellDownward
+			}
+			row[j] = cell
+		}
+		matrix.Cells = append(matrix.Cells, row)
+	}
+
+	// Ребра разных типов между одной парой слайсов попадают в одну клетку
+	for _, edge := range g.Edges {
+		cell := &matrix.Cells[index[edge.From]][index[edge.To]]
+		cell.Imports = append(cell.Imports, edge.Imports...)
+	}
+
+	return matrix
+}
+
+type dsmExporter struct{}
+
+type dsmHTMLExporter struct{}
+
+func init() {
+	Register(dsmExporter{})
+	Register(dsmHTMLExporter{})
+}
+
+func (dsmExporter) Name() string {
+	return "dsm"
+}
+
+func (dsmExporter) FileName() string {
+	return "fsd_structure.dsm.csv"
+}
+
+func (dsmExporter) Export(w io.Writer, report *Report) error {
+	return WriteDSM(w, report.Structure)
+}
+
+// WriteDSM записывает матрицу зависимостей в CSV: первая строка и первый
+// столбец — слайсы в порядке слоев, в клетках — число импортов из слайса
+// строки в слайс столбца.
+func WriteDSM(w io.Writer, structure *model.ProjectStructure) error {
+	matrix := buildDSM(structure)
+	writer := csv.NewWriter(w)
+
+	header := []string{""}
+	for _, slice := range matrix.Slices {
+		header = append(header, slice.ID)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i, row := range matrix.Cells {
+		record := []string{matrix.Slices[i].ID}
+		for _, cell := range row {
+			record = append(record, strconv.Itoa(cell.Count()))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (dsmHTMLExporter) Name() string {
+	return "dsm-html"
+}
+
+func (dsmHTMLExporter) FileName() string {
+	return "fsd_structure.dsm.html"
+}
+
+func (dsmHTMLExporter) Export(w io.Writer, report *Report) error {
+	return writeDSMHTML(w, report)
+}
+
+// Assets подключает к странице с матрицей те же стили и скрипты, что и к
+// основному HTML-отчету.
+func (dsmHTMLExporter) Assets(report *Report) (map[string][]byte, error) {
+	return htmlExporter{}.Assets(report)
+}
+
+// parseDSMTemplate добавляет к шаблону блок "dsm" с таблицей матрицы,
+// который подключается через {{template "dsm" .DSM}}.
+func parseDSMTemplate(t *template.Template) error {
+	if _, err := t.New("dsm").Parse(dsmTableTemplate); err != nil {
+		return i18n.Errorf("ошибка при парсинге шаблона DSM: %v", err)
+	}
+	return nil
+}
+
+func writeDSMHTML(w io.Writer, report *Report) error {
+	assets, err := buildHTMLAssets(report.Config)
+	if err != nil {
+		return err
+	}
+
+	data := htmlReportData{
+		AllowedCyclicalDependencies: []string{},
+		Dependencies:                buildHTMLDependencies(report, report.Dependencies),
+		Messages:                    i18n.Messages(htmlScriptMessages),
+	}
+
+	t, err := parseHTMLTemplate(report, "fsdDSM", dsmHTMLTemplate)
+	if err != nil {
+		return err
+	}
+
+	templateData := struct {
+		DSM        *dsmMatrix
+		Assets     HTMLAssets
+		ReportData htmlReportData
+	}{
+		DSM:        buildDSM(report.Structure),
+		Assets:     assets,
+		ReportData: data,
+	}
+	if err := t.Execute(w, templateData); err != nil {
+		return i18n.Errorf("ошибка при генерации HTML: %v", err)
+	}
+	return nil
+}
+
+const dsmTableTemplate = `<p class="dsm-legend">
+    {{t "Строки — импортирующие слайсы, столбцы — импортируемые, в клетках — число импортов."}}
+    {{t "Клетки ниже диагонали — импорты из нижележащего слоя в вышележащий."}}
+    {{t "Нажмите на клетку, чтобы увидеть места импорта."}}
+</p>
+<div class="dsm-scroll">
+    <table class="dsm-table">
+        <thead>
+            <tr>
+                <th></th>
+                {{range .Slices}}<th class="dsm-col dsm-layer-{{.Layer}}" title="{{.ID}}"><span>{{.ID}}</span></th>{{end}}
+            </tr>
+        </thead>
+        <tbody>
+            {{range $i, $row := .Cells}}
+            <tr>
+                {{with index $.Slices $i}}<th class="dsm-row dsm-layer-{{.Layer}}">{{.ID}}</th>{{end}}
+                {{range $row}}<td class="dsm-cell dsm-{{.Kind}}"{{if .Imports}} data-from="{{.From}}" data-to="{{.To}}" title="{{.From}} → {{.To}}: {{.Count}}"{{end}}>{{if .Imports}}{{.Count}}{{end}}</td>{{end}}
+            </tr>
+            {{end}}
+        </tbody>
+    </table>
+</div>
+<div class="dsm-sites" id="dsm-sites"></div>`
+
+const dsmHTMLTemplate = `<!DOCTYPE html>
+<html lang="{{locale}}">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>FSD Dependency Structure Matrix</title>
+    {{.Assets.Styles}}
+</head>
+<body>
+    <div class="container">
+        <h1>{{t "Матрица зависимостей (DSM)"}}</h1>
+        <div class="dsm" id="dsm">
+            {{template "dsm" .DSM}}
+        </div>
+    </div>
+    <script>window.FSD_REPORT = {{.ReportData}};</script>
+    {{.Assets.Scripts}}
+</body>
+</html>`
@@ -0,0 +1,281 @@
+package exporter
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+// Stdout — цель вывода, означающая стандартный вывод вместо файла.
+const Stdout = "-"
+
+const (
+	OverwriteAlways = "always"
+	OverwriteNever  = "never"
+)
+
+// Exporter формирует отчет в одном формате.
+type Exporter interface {
+	// Name — имя формата в outputFormats.
+	Name() string
+	// FileName — имя файла в outputDir, куда по умолчанию пишется отчет;
+	// Stdout означает стандартный вывод.
+	FileName() string
+	Export(w io.Writer, report *Report) error
+}
+
+// AssetExporter — экспортер, которому рядом с отчетом нужны дополнительные
+// файлы (например, стили и скрипты HTML-отчета).
+type AssetExporter interface {
+	Exporter
+	// Assets возвращает содержимое файлов по путям относительно директории
+	// отчета; nil, если дополнительные файлы не нужны.
+	Assets(report *Report) (map[string][]byte, error)
+}
+
+// Report — результаты анализа, приведенные к типам пакета dependencies.
+type Report struct {
+	Structure         *model.ProjectStructure
+	Config            *config.Config
+	Dependencies      []dependencies.Dependency
+	APIIssues         []dependencies.APIIssue
+	BarrelIssues      []dependencies.BarrelIssue
+	SegmentViolations []dependencies.SegmentViolation
+	SegmentCycles     []dependencies.SegmentCycle
+}
+
+func NewReport(structure *model.ProjectStructure, cfg *config.Config) *Report {
+	report := &Report{
+		Structure:         structure,
+		Config:            cfg,
+		Dependencies:      structureDependencies(structure),
+		APIIssues:         structure.APIIssues,
+		BarrelIssues:      structure.BarrelIssues,
+		SegmentViolations: structure.SegmentViolations,
+		SegmentCycles:     structure.SegmentCycles,
+	}
+
+	if report.APIIssues == nil {
+		report.APIIssues = []dependencies.APIIssue{}
+	}
+	if report.BarrelIssues == nil {
+		report.BarrelIssues = []dependencies.BarrelIssue{}
+	}
+	if report.SegmentViolations == nil {
+		report.SegmentViolations = []dependencies.SegmentViolation{}
+	}
+	if report.SegmentCycles == nil {
+		report.SegmentCycles = []dependencies.SegmentCycle{}
+	}
+
+	return report
+}
+
+func structureDependencies(structure *model.ProjectStructure) []dependencies.Dependency {
+	if structure.Dependencies == nil {
+		return []dependencies.Dependency{}
+	}
+	return structure.Dependencies
+}
+
+var registry = make(map[string]Exporter)
+
+// Register добавляет встроенный экспортер. Повторная регистрация формата
+// заменяет предыдущий экспортер.
+func Register(e Exporter) {
+	registry[e.Name()] = e
+}
+
+// Lookup возвращает экспортер формата: встроенный или внешний, описанный
+// в externalExporters конфигурации.
+func Lookup(name string, cfg *config.Config) (Exporter, bool) {
+	if e, ok := registry[name]; ok {
+		return e, true
+	}
+	if cfg != nil {
+		if external, ok := cfg.ExternalExporters[name]; ok {
+			return &externalExporter{name: name, config: external}, true
+		}
+	}
+	return nil, false
+}
+
+// Names возвращает отсортированные имена встроенных форматов.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OutputPath возвращает цель вывода экспортера: путь из outputs.<формат>.path
+// или файл экспортера в outputDir.
+func OutputPath(e Exporter, cfg *config.Config) string {
+	if cfg != nil {
+		if output, ok := cfg.Outputs[e.Name()]; ok && output.Path != "" {
+			return output.Path
+		}
+	}
+
+	if e.FileName() == Stdout {
+		return Stdout
+	}
+
+	return filepath.Join(outputDirOf(cfg), e.FileName())
+}
+
+func outputDirOf(cfg *config.Config) string {
+	if cfg != nil && cfg.OutputDir != "" {
+		return cfg.OutputDir
+	}
+	return "./dist"
+}
+
+func overwritePolicy(name string, cfg *config.Config) string {
+	if cfg == nil {
+		return OverwriteAlways
+	}
+	if output, ok := cfg.Outputs[name]; ok && output.Overwrite != "" {
+		return output.Overwrite
+	}
+	if cfg.Overwrite != "" {
+		return cfg.Overwrite
+	}
+	return OverwriteAlways
+}
+
+// Run формирует отчет формата name и записывает его в цель вывода.
+// Возвращает путь к созданному файлу или Stdout.
+func Run(name string, structure *model.ProjectStructure, cfg *config.Config) (string, error) {
+	e, ok := Lookup(name, cfg)
+	if !ok {
+		return "", i18n.Errorf("неподдерживаемый формат вывода: %s", name)
+	}
+
+	target := OutputPath(e, cfg)
+	report := NewReport(structure, cfg)
+	write := func(w io.Writer) error {
+		return e.Export(w, report)
+	}
+
+	if err := writeOutput(target, overwritePolicy(name, cfg), write); err != nil {
+		return "", err
+	}
+
+	if assetExporter, ok := e.(AssetExporter); ok {
+		if err := writeAssets(assetExporter, report, target, cfg); err != nil {
+			return "", err
+		}
+	}
+
+	return target, nil
+}
+
+// writeAssets сохраняет дополнительные файлы экспортера рядом с отчетом,
+// а при выводе в stdout — в outputDir.
+func writeAssets(e AssetExporter, report *Report, target string, cfg *config.Config) error {
+	assets, err := e.Assets(report)
+	if err != nil {
+		return i18n.Errorf("не удалось подготовить ресурсы отчета: %v", err)
+	}
+
+	dir := filepath.Dir(target)
+	if target == Stdout {
+		dir = outputDirOf(cfg)
+	}
+
+	names := make([]string, 0, len(assets))
+	for name := range assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		assetPath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(assetPath), 0755); err != nil {
+			return i18n.Errorf("не удалось создать директорию для ресурсов: %v", err)
+		}
+		if err := os.WriteFile(assetPath, assets[name], 0644); err != nil {
+			return i18n.Errorf("не удалось записать ресурс %s: %v", assetPath, err)
+		}
+	}
+
+	return nil
+}
+
+// writeOutput записывает отчет во временный файл рядом с target и
+// переименовывает его, поэтому неудачный экспорт не оставляет обрезанный
+// отчет. При overwrite: never файл target резервируется через O_EXCL до
+// записи: два одновременных запуска не перезапишут отчеты друг друга.
+func writeOutput(target, overwrite string, write func(io.Writer) error) error {
+	if target == Stdout {
+		return write(os.Stdout)
+	}
+
+	switch overwrite {
+	case OverwriteAlways, OverwriteNever:
+	default:
+		return i18n.Errorf("неизвестная политика перезаписи %q (ожидается always или never)", overwrite)
+	}
+
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return i18n.Errorf("не удалось создать директорию для вывода: %v", err)
+	}
+
+	reserved := false
+	if overwrite == OverwriteNever {
+		placeholder, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			return i18n.Errorf("файл %s уже существует (overwrite: never)", target)
+		}
+		if err != nil {
+			return i18n.Errorf("не удалось создать файл %s: %v", target, err)
+		}
+		placeholder.Close()
+		reserved = true
+	}
+
+	tempFile, err := os.CreateTemp(dir, "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		if reserved {
+			os.Remove(target)
+		}
+		return i18n.Errorf("не удалось создать файл %s: %v", target, err)
+	}
+	tempPath := tempFile.Name()
+	cleanup := func() {
+		os.Remove(tempPath)
+		if reserved {
+			os.Remove(target)
+		}
+	}
+
+	if err := write(tempFile); err != nil {
+		tempFile.Close()
+		cleanup()
+		return err
+	}
+	if err := tempFile.Chmod(0644); err != nil {
+		tempFile.Close()
+		cleanup()
+		return i18n.Errorf("не удалось записать файл %s: %v", target, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		cleanup()
+		return i18n.Errorf("не удалось записать файл %s: %v", target, err)
+	}
+	if err := os.Rename(tempPath, target); err != nil {
+		cleanup()
+		return i18n.Errorf("не удалось записать файл %s: %v", target, err)
+	}
+	return nil
+}
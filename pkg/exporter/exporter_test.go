@@ -1,13 +1,26 @@
 package exporter
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
+	"fsd-crawler/pkg/analyzer"
 	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
 	"fsd-crawler/pkg/model"
 )
 
@@ -218,34 +231,1447 @@ func TestExportJSON(t *testing.T) {
 	}
 
 	// Декодируем JSON
-	var decodedStructure model.ProjectStructure
-	if err := json.Unmarshal(content, &decodedStructure); err != nil {
+	report, err := ReadJSONReport(bytes.NewReader(content))
+	if err != nil {
 		t.Fatalf("Failed to decode JSON: %v", err)
 	}
 
-	// Проверяем, что данные соответствуют исходной структуре
+	if report.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("JSON schemaVersion = %s; want %s", report.SchemaVersion, ReportSchemaVersion)
+	}
+
+	// Проверяем, что узлы соответствуют исходной структуре
+	expectedNodes := []string{
+		"layer:app",
+		"slice:app",
+		"segment:app/routes",
+		"file:app/routes/routes.ts",
+		"layer:entities",
+		"slice:entities/user",
+		"segment:entities/user/api",
+		"file:entities/user/api/userApi.ts",
+		"segment:entities/user/model",
+		"file:entities/user/model/user.ts",
+	}
+	var nodes []string
+	for _, node := range report.Nodes {
+		nodes = append(nodes, node.ID)
+	}
+	if !reflect.DeepEqual(nodes, expectedNodes) {
+		t.Errorf("JSON nodes = %v; want %v", nodes, expectedNodes)
+	}
+
+	decodedStructure := report.Structure()
 	if len(decodedStructure.Layers) != len(structure.Layers) {
 		t.Errorf("JSON Layers length = %d; want %d", len(decodedStructure.Layers), len(structure.Layers))
 	}
+	if len(decodedStructure.Layers) > 1 && decodedStructure.Layers[1].Slices[0].Name != "user" {
+		t.Errorf("JSON user slice name = %s; want user", decodedStructure.Layers[1].Slices[0].Name)
+	}
+}
+
+func TestJSONReport(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	report := BuildJSONReport(NewReport(structure, &config.Config{SrcDir: "src"}), time.Unix(0, 0))
+
+	if report.Metadata.GeneratedAt != "1970-01-01T00:00:00Z" {
+		t.Errorf("generatedAt = %s", report.Metadata.GeneratedAt)
+	}
+
+	var edges []string
+	for _, edge := range report.Edges {
+		edges = append(edges, edge.ID+" "+edge.From+"->"+edge.To)
+	}
+	expectedEdges := []string{
+		"edge:app/routes/routes.ts:0: slice:app->slice:entities/user",
+		"edge:app/routes/routes.ts:0:#1 slice:app->slice:entities/user",
+		"edge:entities/user/api/userApi.ts:0: slice:entities/user->slice:app",
+	}
+	if !reflect.DeepEqual(edges, expectedEdges) {
+		t.Errorf("edges = %v; want %v", edges, expectedEdges)
+	}
+
+	if len(report.Violations) == 0 {
+		t.Fatalf("expected violations in report")
+	}
+	for _, violation := range report.Violations {
+		if violation.Edge == "" {
+			t.Errorf("violation %s is not linked to an edge", violation.Rule)
+		}
+	}
+
+	expectedMetrics := []SliceMetrics{
+		{ID: "slice:app", Files: 1, FanIn: 1, FanOut: 1, ImportsIn: 1, ImportsOut: 2, Instability: 0.5},
+		{ID: "slice:entities/user", Files: 2, FanIn: 1, FanOut: 1, ImportsIn: 2, ImportsOut: 1, Instability: 0.5},
+	}
+	if !reflect.DeepEqual(report.Metrics.Slices, expectedMetrics) {
+		t.Errorf("metrics = %+v; want %+v", report.Metrics.Slices, expectedMetrics)
+	}
+
+	// Повторное построение дает тот же документ независимо от порядка зависимостей
+	structure.Dependencies[0], structure.Dependencies[2] = structure.Dependencies[2], structure.Dependencies[0]
+	again := BuildJSONReport(NewReport(structure, &config.Config{SrcDir: "src"}), time.Unix(0, 0))
+	if !reflect.DeepEqual(report, again) {
+		t.Errorf("report is not deterministic")
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(report); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	decoded, err := ReadJSONReport(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSONReport failed: %v", err)
+	}
+	if got := decoded.Structure().Dependencies; len(got) != 3 || got[2].Type != dependencies.DependencyCyclical {
+		t.Errorf("round trip dependencies = %+v", got)
+	}
+
+	if _, err := ReadJSONReport(strings.NewReader(`{"schemaVersion": "2.0.0"}`)); err == nil {
+		t.Errorf("expected error for unsupported schema version")
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(ReportSchema, &schema); err != nil {
+		t.Errorf("embedded schema is not valid JSON: %v", err)
+	}
+}
+
+func TestJSONReportInternalEdges(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	toModel := dependencies.Dependency{
+		FromLayer: "entities", FromSlice: "user", FromSegment: "api", ToLayer: "entities", ToSlice: "user", ToSegment: "model",
+		FromFile: "entities/user/api/userApi.ts", ToFile: "entities/user/model/user.ts", Line: 1, ImportPath: "../model/user",
+	}
+	toAPI := dependencies.Dependency{
+		FromLayer: "entities", FromSlice: "user", FromSegment: "model", ToLayer: "entities", ToSlice: "user", ToSegment: "api",
+		FromFile: "entities/user/model/user.ts", ToFile: "entities/user/api/userApi.ts", Line: 2, ImportPath: "../api/userApi",
+	}
+	structure.InternalImports = []dependencies.Dependency{toModel, toAPI}
+	structure.SegmentCycles = []dependencies.SegmentCycle{{
+		Layer: "entities", Slice: "user", Segments: []string{"api", "model"}, Imports: []dependencies.Dependency{toModel, toAPI},
+	}}
+
+	report := BuildJSONReport(NewReport(structure, &config.Config{}), time.Time{})
+	if len(report.Edges) != 3 || len(report.InternalEdges) != 2 {
+		t.Fatalf("edges = %d, internal edges = %d; want 3 and 2", len(report.Edges), len(report.InternalEdges))
+	}
+	expected := []string{"edge:entities/user/api/userApi.ts:1:../model/user", "edge:entities/user/model/user.ts:2:../api/userApi"}
+	if cycles := report.Issues.SegmentCycles; len(cycles) != 1 || !reflect.DeepEqual(cycles[0].Edges, expected) {
+		t.Errorf("segment cycles = %+v; want edges %v", cycles, expected)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(report); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	decoded, err := ReadJSONReport(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSONReport failed: %v", err)
+	}
+	restored := decoded.Structure()
+	if len(restored.Dependencies) != 3 || !reflect.DeepEqual(restored.InternalImports, structure.InternalImports) {
+		t.Errorf("internal imports = %+v; want %+v", restored.InternalImports, structure.InternalImports)
+	}
+	if cycles := restored.SegmentCycles; len(cycles) != 1 || !reflect.DeepEqual(cycles[0].Imports, structure.SegmentCycles[0].Imports) {
+		t.Errorf("restored segment cycles = %+v", cycles)
+	}
+}
+
+func createTestStructureWithDependencies() *model.ProjectStructure {
+	structure := createTestStructure()
+	structure.Dependencies = []dependencies.Dependency{
+		dependencies.Dependency{
+			FromLayer: "app", FromSlice: "app", ToLayer: "entities", ToSlice: "user",
+			Type: dependencies.DependencyNormal, FromFile: "app/routes/routes.ts", ToFile: "entities/user/model/user.ts",
+		},
+		dependencies.Dependency{
+			FromLayer: "app", FromSlice: "app", ToLayer: "entities", ToSlice: "user",
+			Type: dependencies.DependencyNormal, FromFile: "app/routes/routes.ts", ToFile: "entities/user/api/userApi.ts",
+		},
+		dependencies.Dependency{
+			FromLayer: "entities", FromSlice: "user", ToLayer: "app", ToSlice: "app",
+			Type: dependencies.DependencyCyclical, FromFile: "entities/user/api/userApi.ts", ToFile: "app/routes/routes.ts",
+		},
+	}
+	return structure
+}
+
+func TestWriteDOT(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, structure, false); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+
+	dot := buf.String()
+	expectedStrings := []string{
+		"digraph fsd {",
+		`subgraph "cluster_app" {`,
+		`subgraph "cluster_entities" {`,
+		`"__layer_0" -> "__layer_1" [style=invis];`,
+		`"app" -> "entities/user" [color="#28a745", label="2"];`,
+		`"entities/user" -> "app" [color="#dc3545", constraint=false, penwidth=2];`,
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("DOT output does not contain %q:\n%s", expected, dot)
+		}
+	}
+
+	buf.Reset()
+	if err := WriteDOT(&buf, structure, true); err != nil {
+		t.Fatalf("WriteDOT with file detail failed: %v", err)
+	}
+
+	dot = buf.String()
+	expectedStrings = []string{
+		`subgraph "cluster_entities/user" {`,
+		`"entities/user/model/user.ts" [label="model/user.ts"];`,
+		`"app/routes/routes.ts" -> "entities/user/api/userApi.ts" [color="#28a745"];`,
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(dot, expected) {
+			t.Errorf("file-level DOT output does not contain %q:\n%s", expected, dot)
+		}
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteMermaid(&buf, structure, false, config.DiagramConfig{}); err != nil {
+		t.Fatalf("WriteMermaid failed: %v", err)
+	}
 
-	// Проверяем первый слой
-	if len(decodedStructure.Layers) > 0 {
-		if decodedStructure.Layers[0].Name != "app" {
-			t.Errorf("JSON first layer name = %s; want app", decodedStructure.Layers[0].Name)
+	mermaid := buf.String()
+	expectedStrings := []string{
+		"flowchart TB",
+		`subgraph layer_0["app"]`,
+		`n0["app"]`,
+		`n1["user"]`,
+		"layer_0 ~~~ layer_1",
+		"n0 -->|2| n1",
+		"n1 --> n0",
+		"linkStyle 2 stroke:#dc3545,stroke-width:3px",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(mermaid, expected) {
+			t.Errorf("Mermaid output does not contain %q:\n%s", expected, mermaid)
 		}
 	}
 
-	// Проверяем второй слой
-	if len(decodedStructure.Layers) > 1 {
-		if decodedStructure.Layers[1].Name != "entities" {
-			t.Errorf("JSON second layer name = %s; want entities", decodedStructure.Layers[1].Name)
+	buf.Reset()
+	opts := config.DiagramConfig{ViolationsOnly: true, MaxEdges: 1}
+	if err := WriteMermaid(&buf, structure, false, opts); err != nil {
+		t.Fatalf("WriteMermaid with limits failed: %v", err)
+	}
+
+	mermaid = buf.String()
+	if strings.Contains(mermaid, "n0 -->|2| n1") {
+		t.Errorf("Mermaid output with violationsOnly contains normal edge:\n%s", mermaid)
+	}
+	if !strings.Contains(mermaid, "%% Скрыто узлов: 0, ребер: 1") {
+		t.Errorf("Mermaid output does not report hidden edges:\n%s", mermaid)
+	}
+
+	buf.Reset()
+	opts = config.DiagramConfig{Layers: []string{"entities"}}
+	if err := WriteMermaid(&buf, structure, false, opts); err != nil {
+		t.Fatalf("WriteMermaid with layer filter failed: %v", err)
+	}
+	if mermaid = buf.String(); strings.Contains(mermaid, `["app"]`) {
+		t.Errorf("Mermaid output contains filtered layer:\n%s", mermaid)
+	}
+}
+
+func TestWritePlantUML(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WritePlantUML(&buf, structure, false, config.DiagramConfig{}); err != nil {
+		t.Fatalf("WritePlantUML failed: %v", err)
+	}
+
+	plantUML := buf.String()
+	expectedStrings := []string{
+		"@startuml",
+		`package "entities" as layer_1 {`,
+		`rectangle "user" as n1`,
+		"n0 -[hidden]-> n1",
+		"n0 -[#28a745]-> n1 : 2",
+		"n1 -[#dc3545,bold]-> n0",
+		"@enduml",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(plantUML, expected) {
+			t.Errorf("PlantUML output does not contain %q:\n%s", expected, plantUML)
 		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	structure.Dependencies = append(structure.Dependencies, dependencies.Dependency{
+		FromLayer: "app", FromSlice: "app", ToLayer: "entities", ToSlice: "user", ToSegment: "model",
+		Type: dependencies.DependencyNormal, FromFile: "app/routes/routes.ts", ToFile: "entities/user/model/user.ts",
+		Line: 7, ImportPath: "entities/user/model/user",
+	})
+
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, structure, &config.Config{SrcDir: "./src"}); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Failed to decode SARIF: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("Unexpected SARIF log: version %s, %d runs", log.Version, len(log.Runs))
+	}
+	if len(log.Runs[0].Tool.Driver.Rules) != 4 {
+		t.Errorf("SARIF has %d rules; want 4", len(log.Runs[0].Tool.Driver.Rules))
+	}
+
+	counts := make(map[string]int)
+	foundRegion := false
+	for _, result := range log.Runs[0].Results {
+		counts[result.RuleID]++
+		location := result.Locations[0].PhysicalLocation
+		if !strings.HasPrefix(location.ArtifactLocation.URI, "src/") {
+			t.Errorf("Result URI %s is not relative to repository root", location.ArtifactLocation.URI)
+		}
+		if location.Region != nil && location.Region.StartLine == 7 && result.RuleID == RulePublicAPIBypass {
+			foundRegion = true
+		}
+	}
+	if !foundRegion {
+		t.Errorf("SARIF does not contain public API bypass at line 7")
+	}
+
+	expected := map[string]int{RuleUpwardImport: 1, RulePublicAPIBypass: 3, RuleCycle: 4}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("SARIF results by rule = %v; want %v", counts, expected)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, structure, nil); err != nil {
+		t.Fatalf("WriteJUnit failed: %v", err)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode JUnit report: %v", err)
+	}
+
+	if len(report.Suites) != 4 {
+		t.Fatalf("JUnit report has %d suites; want 4", len(report.Suites))
+	}
+	if report.Tests != 8 {
+		t.Errorf("JUnit report has %d tests; want 8", report.Tests)
+	}
+
+	upward := report.Suites[0]
+	if upward.Name != RuleUpwardImport || upward.Failures != 1 {
+		t.Errorf("Suite %s has %d failures; want %s with 1", upward.Name, upward.Failures, RuleUpwardImport)
+	}
+	for _, testCase := range upward.TestCases {
+		if testCase.Name == "entities/user" && testCase.Failure == nil {
+			t.Errorf("Test case entities/user does not fail for upward import")
+		}
+		if testCase.Name == "app" && testCase.Failure != nil {
+			t.Errorf("Test case app fails for upward import: %s", testCase.Failure.Text)
+		}
+	}
+}
 
-		// Проверяем слайс user
-		if len(decodedStructure.Layers[1].Slices) > 0 {
-			if decodedStructure.Layers[1].Slices[0].Name != "user" {
-				t.Errorf("JSON user slice name = %s; want user", decodedStructure.Layers[1].Slices[0].Name)
+func TestWriteCheckstyle(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteCheckstyle(&buf, structure, &config.Config{SrcDir: "src"}); err != nil {
+		t.Fatalf("WriteCheckstyle failed: %v", err)
+	}
+
+	var report checkstyleReport
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode Checkstyle report: %v", err)
+	}
+
+	var files []string
+	for _, file := range report.Files {
+		files = append(files, file.Name)
+	}
+	expectedFiles := []string{"src/app/routes/routes.ts", "src/entities/user/api/userApi.ts"}
+	if !reflect.DeepEqual(files, expectedFiles) {
+		t.Errorf("Checkstyle files = %v; want %v", files, expectedFiles)
+	}
+
+	for _, file := range report.Files {
+		for _, e := range file.Errors {
+			if e.Source == "" || e.Severity == "" || e.Message == "" {
+				t.Errorf("Incomplete Checkstyle error in %s: %+v", file.Name, e)
 			}
 		}
 	}
-} 
\ No newline at end of file
+}
+
+func TestWriteCodeQuality(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteCodeQuality(&buf, structure, nil); err != nil {
+		t.Fatalf("WriteCodeQuality failed: %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("Failed to decode Code Quality report: %v", err)
+	}
+	if len(issues) == 0 {
+		t.Fatalf("Code Quality report is empty")
+	}
+
+	fingerprints := make(map[string]bool)
+	for _, issue := range issues {
+		if fingerprints[issue.Fingerprint] {
+			t.Errorf("Duplicate fingerprint %s", issue.Fingerprint)
+		}
+		fingerprints[issue.Fingerprint] = true
+
+		if issue.Location.Lines.Begin < 1 {
+			t.Errorf("Issue %s has line %d; want >= 1", issue.CheckName, issue.Location.Lines.Begin)
+		}
+	}
+
+	shifted := createTestStructureWithDependencies()
+	for i, dep := range shifted.Dependencies {
+		dep.Line += 10
+		shifted.Dependencies[i] = dep
+	}
+
+	buf.Reset()
+	if err := WriteCodeQuality(&buf, shifted, nil); err != nil {
+		t.Fatalf("WriteCodeQuality failed: %v", err)
+	}
+	var shiftedIssues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &shiftedIssues); err != nil {
+		t.Fatalf("Failed to decode Code Quality report: %v", err)
+	}
+	for _, issue := range shiftedIssues {
+		if !fingerprints[issue.Fingerprint] {
+			t.Errorf("Fingerprint of %s changed after shifting lines", issue.CheckName)
+		}
+	}
+}
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	structure := &model.ProjectStructure{
+		Dependencies: []dependencies.Dependency{
+			dependencies.Dependency{
+				FromLayer: "entities", FromSlice: "user", ToLayer: "features", ToSlice: "auth",
+				Type: dependencies.DependencyCyclical, FromFile: "entities/user/model/user.ts",
+				ToFile: "features/auth/index.ts", Line: 3, ImportPath: "features/auth",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGitHubAnnotations(&buf, structure, &config.Config{SrcDir: "src"}); err != nil {
+		t.Fatalf("WriteGitHubAnnotations failed: %v", err)
+	}
+
+	expected := "::error file=src/entities/user/model/user.ts,line=3,title=fsd/upward-import::"
+	if !strings.HasPrefix(buf.String(), expected) {
+		t.Errorf("GitHub annotation = %q; want prefix %q", buf.String(), expected)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("GitHub annotations = %q; want exactly one line", buf.String())
+	}
+}
+
+func TestRunOutputs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "fsd-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	structure := createTestStructureWithDependencies()
+	dotPath := filepath.Join(tempDir, "docs", "fsd.dot")
+	cfg := &config.Config{
+		OutputDir: filepath.Join(tempDir, "dist"),
+		Outputs: map[string]config.OutputConfig{
+			"dot": {Path: dotPath, Overwrite: OverwriteNever},
+		},
+		ExternalExporters: map[string]config.ExternalExporter{
+			"copy": {Command: "cat", FileName: "copy.json"},
+		},
+	}
+
+	if _, ok := Lookup("unknown", cfg); ok {
+		t.Errorf("Lookup found exporter for unknown format")
+	}
+
+	path, err := Run("dot", structure, cfg)
+	if err != nil {
+		t.Fatalf("Run(dot) failed: %v", err)
+	}
+	if path != dotPath {
+		t.Errorf("Run(dot) path = %s; want %s", path, dotPath)
+	}
+	if _, err := Run("dot", structure, cfg); err == nil {
+		t.Errorf("Run(dot) overwrote existing file with overwrite: never")
+	}
+
+	path, err = Run("json", structure, cfg)
+	if err != nil {
+		t.Fatalf("Run(json) failed: %v", err)
+	}
+	if path != filepath.Join(cfg.OutputDir, "fsd_structure.json") {
+		t.Errorf("Run(json) path = %s; want default file in outputDir", path)
+	}
+
+	path, err = Run("copy", structure, cfg)
+	if err != nil {
+		t.Fatalf("Run(copy) failed: %v", err)
+	}
+	expected, _ := os.ReadFile(filepath.Join(cfg.OutputDir, "fsd_structure.json"))
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read external exporter output: %v", err)
+	}
+	if string(content) != string(expected) {
+		t.Errorf("External exporter did not receive JSON report on stdin")
+	}
+
+	if github, ok := Lookup("github", cfg); !ok || OutputPath(github, cfg) != Stdout {
+		t.Errorf("github exporter does not write to stdout by default")
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "fsd.dot")
+	failing := func(w io.Writer) error {
+		io.WriteString(w, "digraph {")
+		return fmt.Errorf("write failed")
+	}
+
+	if err := writeOutput(target, OverwriteAlways, func(w io.Writer) error {
+		_, err := io.WriteString(w, "old")
+		return err
+	}); err != nil {
+		t.Fatalf("writeOutput failed: %v", err)
+	}
+
+	// Неудачный экспорт не обрезает существующий отчет
+	if err := writeOutput(target, OverwriteAlways, failing); err == nil {
+		t.Errorf("writeOutput must return the write error")
+	}
+	if content, _ := os.ReadFile(target); string(content) != "old" {
+		t.Errorf("failed export changed the report: %q", content)
+	}
+
+	// При overwrite: never неудачный экспорт не оставляет файл
+	fresh := filepath.Join(dir, "fresh.dot")
+	if err := writeOutput(fresh, OverwriteNever, failing); err == nil {
+		t.Errorf("writeOutput must return the write error")
+	}
+	if _, err := os.Stat(fresh); !os.IsNotExist(err) {
+		t.Errorf("failed export with overwrite: never left %s", fresh)
+	}
+	if err := writeOutput(target, OverwriteNever, failing); err == nil || !strings.Contains(err.Error(), "overwrite: never") {
+		t.Errorf("writeOutput with an existing file = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files were not removed: %v", entries)
+	}
+}
+
+func TestJSONReportApplyConfig(t *testing.T) {
+	source := &config.Config{
+		SrcDir:       "src",
+		Aliases:      map[string]string{"@": "src"},
+		SegmentRules: []config.SegmentRule{{From: "model", To: "ui"}},
+	}
+	report := BuildJSONReport(NewReport(createTestStructureWithDependencies(), source), time.Unix(0, 0))
+
+	cfg := &config.Config{SrcDir: ".", OutputDir: "reports"}
+	report.ApplyConfig(cfg)
+
+	if cfg.SrcDir != "src" || cfg.OutputDir != "reports" {
+		t.Errorf("ApplyConfig srcDir = %s, outputDir = %s", cfg.SrcDir, cfg.OutputDir)
+	}
+	if !reflect.DeepEqual(cfg.SegmentRules, source.SegmentRules) {
+		t.Errorf("ApplyConfig segmentRules = %+v; want %+v", cfg.SegmentRules, source.SegmentRules)
+	}
+	if !reflect.DeepEqual(cfg.CustomLayers, model.KnownLayers) {
+		t.Errorf("ApplyConfig customLayers = %v; want %v", cfg.CustomLayers, model.KnownLayers)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	cfg := &config.Config{
+		SrcDir: "src",
+		Markdown: config.MarkdownConfig{
+			RepoURL: "https://gitlab.com/org/repo/-/blob/main/",
+			Mermaid: true,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, structure, cfg); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+
+	markdown := buf.String()
+	expectedStrings := []string{
+		"❌ Найдено нарушений: **6**.",
+		"| app | 1 | 1 | 2 |",
+		"| entities | 1 | 2 | 1 |",
+		"| `fsd/upward-import` | [src/entities/user/api/userApi.ts](https://gitlab.com/org/repo/-/blob/main/src/entities/user/api/userApi.ts) |",
+		"| entities/user | 1 | 1 | 2 / 1 | 0.50 |",
+		"```mermaid\nflowchart TB",
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(markdown, expected) {
+			t.Errorf("Markdown output does not contain %q:\n%s", expected, markdown)
+		}
+	}
+
+	cfg.Markdown = config.MarkdownConfig{MaxViolations: 1, TopSlices: -1}
+	buf.Reset()
+	if err := WriteMarkdown(&buf, structure, cfg); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	markdown = buf.String()
+	if !strings.Contains(markdown, "…и еще 5.") || strings.Contains(markdown, "Самые связанные слайсы") || strings.Contains(markdown, "mermaid") {
+		t.Errorf("Markdown output ignores limits:\n%s", markdown)
+	}
+}
+
+func TestMarkdownLocation(t *testing.T) {
+	got := markdownLocation("https://example.com/blob/main/", "src/pages/my page/#1?.tsx", 3)
+	want := "[src/pages/my page/#1?.tsx:3](https://example.com/blob/main/src/pages/my%20page/%231%3F.tsx#L3)"
+	if got != want {
+		t.Errorf("markdownLocation = %s; want %s", got, want)
+	}
+}
+
+func TestWriteMarkdownLayersOrphans(t *testing.T) {
+	doc := &JSONReport{Nodes: []ReportNode{
+		{ID: "slice:features/auth", Kind: NodeSlice, Parent: "layer:features"},
+		{ID: "file:features/auth/index.ts", Kind: NodeFile, Parent: "segment:features/auth/root"},
+	}}
+
+	var buf bytes.Buffer
+	writeMarkdownLayers(&buf, doc)
+	if buf.Len() != 0 {
+		t.Errorf("writeMarkdownLayers without layers = %q; want empty", buf.String())
+	}
+}
+
+func TestWriteGraphFormats(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var graphml bytes.Buffer
+	if err := WriteGraphML(&graphml, structure, false); err != nil {
+		t.Fatalf("WriteGraphML failed: %v", err)
+	}
+	var parsed struct {
+		Graph struct {
+			Nodes []struct {
+				ID    string `xml:"id,attr"`
+				Graph struct {
+					Nodes []struct {
+						ID string `xml:"id,attr"`
+					} `xml:"node"`
+				} `xml:"graph"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(graphml.Bytes(), &parsed); err != nil {
+		t.Fatalf("GraphML is not valid XML: %v", err)
+	}
+	if len(parsed.Graph.Nodes) != 2 || parsed.Graph.Nodes[1].Graph.Nodes[0].ID != "slice:entities/user" {
+		t.Errorf("GraphML hierarchy = %+v", parsed.Graph.Nodes)
+	}
+	if len(parsed.Graph.Edges) != 2 {
+		t.Fatalf("GraphML edges = %+v; want 2", parsed.Graph.Edges)
+	}
+	edge := parsed.Graph.Edges[0]
+	if edge.Source != "slice:app" || edge.Target != "slice:entities/user" || edge.Data[2].Value != "2" {
+		t.Errorf("GraphML first edge = %+v", edge)
+	}
+
+	var gexf bytes.Buffer
+	if err := WriteGEXF(&gexf, structure, true); err != nil {
+		t.Fatalf("WriteGEXF failed: %v", err)
+	}
+	expectedStrings := []string{
+		`<node id="file:entities/user/model/user.ts" label="user.ts" pid="segment:entities/user/model">`,
+		`source="file:app/routes/routes.ts" target="file:entities/user/api/userApi.ts" label="normal" weight="1"`,
+		`source="file:entities/user/api/userApi.ts" target="file:app/routes/routes.ts" label="cyclical" weight="1"`,
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(gexf.String(), expected) {
+			t.Errorf("GEXF output does not contain %q:\n%s", expected, gexf.String())
+		}
+	}
+
+	var cytoscape bytes.Buffer
+	if err := WriteCytoscape(&cytoscape, structure, false); err != nil {
+		t.Fatalf("WriteCytoscape failed: %v", err)
+	}
+	var doc cytoscapeDocument
+	if err := json.Unmarshal(cytoscape.Bytes(), &doc); err != nil {
+		t.Fatalf("Cytoscape JSON is not valid: %v", err)
+	}
+	if len(doc.Elements.Nodes) != 10 || doc.Elements.Nodes[1].Data.Parent != "layer:app" {
+		t.Errorf("Cytoscape nodes = %+v", doc.Elements.Nodes)
+	}
+	expectedEdges := []cytoscapeEdgeData{
+		{ID: "e0", Source: "slice:app", Target: "slice:entities/user", Type: "normal", Weight: 2},
+		{ID: "e1", Source: "slice:entities/user", Target: "slice:app", Type: "cyclical", Weight: 1},
+	}
+	for i, expected := range expectedEdges {
+		if i >= len(doc.Elements.Edges) || doc.Elements.Edges[i].Data != expected {
+			t.Errorf("Cytoscape edges = %+v; want %+v", doc.Elements.Edges, expectedEdges)
+			break
+		}
+	}
+}
+
+func TestHTMLAssets(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	outputDir := t.TempDir()
+	cfg := &config.Config{OutputDir: outputDir}
+
+	htmlPath, err := Run("html", structure, cfg)
+	if err != nil {
+		t.Fatalf("Run html failed: %v", err)
+	}
+	content, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read HTML file: %v", err)
+	}
+	html := string(content)
+	if strings.Contains(html, "https://") || !strings.Contains(html, "var FSDGraph") || !strings.Contains(html, `"source":"entities/user","target":"app"`) {
+		t.Errorf("inline HTML report is not self-contained")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "assets")); !os.IsNotExist(err) {
+		t.Errorf("inline HTML report must not write assets")
+	}
+
+	cfg.HTML.Assets = HTMLAssetsExternal
+	if _, err := Run("html", structure, cfg); err != nil {
+		t.Fatalf("Run html with external assets failed: %v", err)
+	}
+	content, err = os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read HTML file: %v", err)
+	}
+	for _, name := range htmlAssetFiles {
+		if !strings.Contains(string(content), `"assets/`+name+`?v=`) {
+			t.Errorf("HTML report does not link %s", name)
+		}
+		if _, err := os.Stat(filepath.Join(outputDir, "assets", name)); err != nil {
+			t.Errorf("asset %s was not written: %v", name, err)
+		}
+	}
+
+	cfg.HTML.Assets = "cdn"
+	if _, err := Run("html", structure, cfg); err == nil {
+		t.Errorf("expected error for unknown html.assets mode")
+	}
+}
+
+// largeLayoutStructure строит проект из slices слайсов по слоям FSD, в
+// котором каждый слайс импортирует imports случайных слайсов.
+func largeLayoutStructure(slices, imports int) *model.ProjectStructure {
+	random := rand.New(rand.NewSource(1))
+	layers := model.KnownLayers
+	structure := &model.ProjectStructure{}
+	for _, name := range layers {
+		structure.Layers = append(structure.Layers, &model.FSDLayer{Name: name})
+	}
+
+	type sliceRef struct{ layer, name string }
+	var refs []sliceRef
+	for i := 0; i < slices; i++ {
+		layer := structure.Layers[i%len(layers)]
+		name := fmt.Sprintf("slice%d", i)
+		layer.Slices = append(layer.Slices, &model.FSDSlice{
+			Name:     name,
+			Segments: []*model.FSDSegment{{Name: "ui", Files: []string{"index.ts"}}},
+		})
+		refs = append(refs, sliceRef{layer.Name, name})
+	}
+	for i, from := range refs {
+		for j := 0; j < imports; j++ {
+			to := refs[random.Intn(len(refs))]
+			structure.Dependencies = append(structure.Dependencies, dependencies.Dependency{
+				FromLayer: from.layer, FromSlice: from.name, ToLayer: to.layer, ToSlice: to.name,
+				FromFile: fmt.Sprintf("%s/%s/ui/index.ts", from.layer, from.name), Line: i*imports + j + 1,
+				Type: dependencies.DependencyNormal,
+			})
+		}
+	}
+	return structure
+}
+
+func TestBuildLayeredLayoutLargeProject(t *testing.T) {
+	structure := largeLayoutStructure(300, 8)
+
+	start := time.Now()
+	layout := buildLayeredLayout(structure, true)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("buildLayeredLayout took %v on 300 slices", elapsed)
+	}
+	if len(layout.Nodes) != 300 {
+		t.Errorf("expected 300 nodes, got %d", len(layout.Nodes))
+	}
+}
+
+func BenchmarkBuildLayeredLayout(b *testing.B) {
+	structure := largeLayoutStructure(300, 8)
+	for i := 0; i < b.N; i++ {
+		buildLayeredLayout(structure, true)
+	}
+}
+
+func TestCrossingsBelow(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		lower := make([]*layoutVertex, 6)
+		for i := range lower {
+			lower[i] = &layoutVertex{order: float64(i)}
+		}
+		row := make([]*layoutVertex, 6)
+		for i := range row {
+			row[i] = &layoutVertex{order: float64(i)}
+			for j := random.Intn(4); j > 0; j-- {
+				row[i].down = append(row[i].down, lower[random.Intn(len(lower))])
+			}
+		}
+
+		expected := 0
+		var segments [][2]float64
+		for _, v := range row {
+			for _, u := range v.down {
+				segments = append(segments, [2]float64{v.order, u.order})
+			}
+		}
+		for i := range segments {
+			for j := i + 1; j < len(segments); j++ {
+				if (segments[i][0]-segments[j][0])*(segments[i][1]-segments[j][1]) < 0 {
+					expected++
+				}
+			}
+		}
+
+		if crossings := crossingsBelow(row); crossings != expected {
+			t.Errorf("crossingsBelow = %d; want %d for %v", crossings, expected, segments)
+		}
+	}
+}
+
+func TestBuildLayeredLayout(t *testing.T) {
+	slice := func(name string) *model.FSDSlice {
+		return &model.FSDSlice{Name: name, Segments: []*model.FSDSegment{{Name: "ui", Files: []string{"index.ts"}}}}
+	}
+	dep := func(fromLayer, fromSlice, toLayer, toSlice string) dependencies.Dependency {
+		return dependencies.Dependency{FromLayer: fromLayer, FromSlice: fromSlice, ToLayer: toLayer, ToSlice: toSlice, Type: dependencies.DependencyNormal}
+	}
+	structure := &model.ProjectStructure{
+		Layers: []*model.FSDLayer{
+			{Name: "shared", Slices: []*model.FSDSlice{slice("api"), slice("ui")}},
+			{Name: "features", Slices: []*model.FSDSlice{slice("auth")}},
+			{Name: "pages", Slices: []*model.FSDSlice{slice("home"), slice("profile")}},
+		},
+		Dependencies: []dependencies.Dependency{
+			dep("pages", "home", "shared", "ui"),
+			dep("pages", "profile", "shared", "api"),
+			dep("pages", "home", "features", "auth"),
+			dep("shared", "api", "features", "auth"),
+		},
+	}
+
+	layout := buildLayeredLayout(structure, true)
+
+	var bands []string
+	for _, band := range layout.Bands {
+		bands = append(bands, band.Layer)
+	}
+	if strings.Join(bands, ",") != "pages,features,shared" {
+		t.Fatalf("bands must follow model.KnownLayers order, got %v", bands)
+	}
+
+	nodes := make(map[string]layoutNode)
+	for _, node := range layout.Nodes {
+		nodes[node.ID] = node
+		band := layout.Bands[indexOf(bands, node.Layer)]
+		if node.Y < band.Y || node.Y > band.Y+band.Height {
+			t.Errorf("node %s is outside of its band: %v", node.ID, node.Y)
+		}
+	}
+	if len(nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d", len(nodes))
+	}
+
+	// Два длинных ребра pages → shared не должны пересекаться
+	if (nodes["pages/home"].X < nodes["pages/profile"].X) != (nodes["shared/ui"].X < nodes["shared/api"].X) {
+		t.Errorf("slices are ordered with a crossing: %+v", layout.Nodes)
+	}
+
+	for _, edge := range layout.Edges {
+		switch edge.From + "->" + edge.To {
+		case "shared/api->features/auth":
+			if !edge.Upward {
+				t.Errorf("import from a lower layer must be marked as upward")
+			}
+		case "pages/home->shared/ui", "pages/profile->shared/api":
+			if edge.Upward || len(edge.Points) != 1 {
+				t.Errorf("edge %s->%s must pass through the features band: %+v", edge.From, edge.To, edge)
+			}
+		default:
+			if edge.Upward {
+				t.Errorf("edge %s->%s is not upward", edge.From, edge.To)
+			}
+		}
+	}
+
+	cfg := &config.Config{OutputDir: t.TempDir()}
+	cfg.HTML.Layout = "grid"
+	if _, err := Run("html", structure, cfg); err == nil {
+		t.Errorf("expected error for unknown html.layout mode")
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestWriteDSM(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+
+	var buf bytes.Buffer
+	if err := WriteDSM(&buf, structure); err != nil {
+		t.Fatalf("WriteDSM failed: %v", err)
+	}
+	expected := ",app,entities/user\napp,0,2\nentities/user,1,0\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected DSM CSV:\n%s", buf.String())
+	}
+
+	outputDir := t.TempDir()
+	htmlPath, err := Run("dsm-html", structure, &config.Config{OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("Run dsm-html failed: %v", err)
+	}
+	content, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read DSM HTML file: %v", err)
+	}
+	html := string(content)
+	for _, fragment := range []string{
+		`class="dsm-cell dsm-upward" data-from="entities/user" data-to="app"`,
+		`class="dsm-cell dsm-downward" data-from="app" data-to="entities/user"`,
+		`"importPath":`,
+	} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("DSM HTML does not contain %s", fragment)
+		}
+	}
+
+	htmlPath, err = Run("html", structure, &config.Config{OutputDir: outputDir})
+	if err != nil {
+		t.Fatalf("Run html failed: %v", err)
+	}
+	content, err = os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read HTML file: %v", err)
+	}
+	if !strings.Contains(string(content), `id="dsm"`) || !strings.Contains(string(content), "dsm-upward") {
+		t.Errorf("HTML report does not contain the DSM view")
+	}
+}
+
+func TestHTMLFilters(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	htmlPath, err := Run("html", structure, &config.Config{OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Run html failed: %v", err)
+	}
+	content, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("Failed to read HTML file: %v", err)
+	}
+	html := string(content)
+	for _, fragment := range []string{
+		`id="report-filters"`,
+		`data-filter-type="cyclical"`,
+		`data-from-layer="entities" data-from-slice="user"`,
+		`data-type="cyclical"`,
+		`"files":["entities/user/api/userApi.ts","entities/user/model/user.ts"]`,
+	} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("HTML report does not contain %s", fragment)
+		}
+	}
+}
+
+func TestCollectSources(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"app/routes/routes.ts":         "import { user } from 'entities/user'\n",
+		"entities/user/api/userApi.ts": "import { routes } from 'app/routes'\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	structure := createTestStructureWithDependencies()
+	cfg := &config.Config{SrcDir: srcDir}
+	sources := collectSources(NewReport(structure, cfg))
+	if len(sources) != 2 || sources["app/routes/routes.ts"] != files["app/routes/routes.ts"] {
+		t.Errorf("unexpected embedded sources: %v", sources)
+	}
+
+	// Файл с нарушением встраивается первым, остальные не помещаются в лимит
+	cfg.HTML.SourceMaxBytes = len(files["entities/user/api/userApi.ts"])
+	sources = collectSources(NewReport(structure, cfg))
+	if _, ok := sources["entities/user/api/userApi.ts"]; !ok || len(sources) != 1 {
+		t.Errorf("expected only the violating file within the limit, got %v", sources)
+	}
+
+	cfg.HTML.SourceMaxBytes = -1
+	if sources = collectSources(NewReport(structure, cfg)); len(sources) != 0 {
+		t.Errorf("sourceMaxBytes: -1 must disable embedding, got %v", sources)
+	}
+}
+
+// TestHTMLReportDataRoundTrip проверяет, что render строит по JSON-отчету те
+// же данные HTML-отчета, что и прямой анализ проекта.
+func TestHTMLReportDataRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"entities/user/index.ts":       "export { user } from './model/user'\n",
+		"entities/user/api/userApi.ts": "import { user } from '../model/user'\nexport const load = () => user\n",
+		"entities/user/model/user.ts":  "import { load } from '../api/userApi'\nexport const user = load\n",
+		"pages/home/ui/HomePage.tsx":   "import { user } from 'entities/user'\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{SrcDir: srcDir}
+	direct := NewReport(analyzer.AnalyzeProject(cfg), cfg)
+	if len(direct.Structure.InternalImports) != 3 || len(direct.SegmentCycles) != 1 {
+		t.Fatalf("unexpected analysis: internal imports %+v, segment cycles %+v", direct.Structure.InternalImports, direct.SegmentCycles)
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(BuildJSONReport(direct, time.Time{})); err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	doc, err := ReadJSONReport(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSONReport failed: %v", err)
+	}
+	renderCfg := &config.Config{}
+	doc.ApplyConfig(renderCfg)
+	rendered := NewReport(doc.Structure(), renderCfg)
+
+	if !reflect.DeepEqual(rendered.SegmentCycles, direct.SegmentCycles) {
+		t.Errorf("segment cycles after render = %+v; want %+v", rendered.SegmentCycles, direct.SegmentCycles)
+	}
+	want := buildHTMLReportData(direct, nil, HTMLLayoutLayered)
+	got := buildHTMLReportData(rendered, nil, HTMLLayoutLayered)
+	if !reflect.DeepEqual(got.Dependencies, want.Dependencies) {
+		t.Errorf("dependencies after render = %+v; want %+v", got.Dependencies, want.Dependencies)
+	}
+	if !reflect.DeepEqual(got.InternalImports, want.InternalImports) {
+		t.Errorf("internal imports after render = %+v; want %+v", got.InternalImports, want.InternalImports)
+	}
+	if !reflect.DeepEqual(got.Layout, want.Layout) {
+		t.Errorf("layout after render = %+v; want %+v", got.Layout, want.Layout)
+	}
+	if !reflect.DeepEqual(got.Tree, want.Tree) {
+		t.Errorf("tree after render = %+v; want %+v", got.Tree, want.Tree)
+	}
+}
+
+func TestSourceHandler(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"app/routes/routes.ts": "import { user } from 'entities/user'\n",
+		".env":                 "SECRET=1\n",
+		"app/routes/secret.ts": "export const secret = 1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewSourceHandler(NewReport(createTestStructureWithDependencies(), &config.Config{SrcDir: srcDir}))
+	cases := map[string]int{
+		"app/routes/routes.ts":           http.StatusOK,
+		"app/routes/../routes/routes.ts": http.StatusOK,
+		".env":                           http.StatusNotFound,
+		"app/routes/secret.ts":           http.StatusNotFound,
+		"../" + filepath.Base(srcDir):    http.StatusNotFound,
+		"entities/user/api/userApi.ts":   http.StatusNotFound, // в отчете, но нет на диске
+	}
+	for file, status := range cases {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest("GET", HTMLSourceRoute+file, nil))
+		if recorder.Code != status {
+			t.Errorf("GET %s = %d, expected %d", file, recorder.Code, status)
+		}
+		if status == http.StatusOK && recorder.Body.String() != files["app/routes/routes.ts"] {
+			t.Errorf("GET %s body = %q", file, recorder.Body.String())
+		}
+	}
+}
+
+func TestBuildTreemap(t *testing.T) {
+	structure := createTestStructureWithDependencies()
+	structure.Layers[0].Slices[0].Segments[0].Stats = map[string]model.FileStats{"routes.ts": {Lines: 10, Bytes: 200}}
+	user := structure.Layers[1].Slices[0]
+	user.Segments[0].Stats = map[string]model.FileStats{"userApi.ts": {Lines: 30, Bytes: 600}}
+	user.Segments[1].Stats = map[string]model.FileStats{"user.ts": {Lines: 5, Bytes: 100}}
+
+	report := NewReport(structure, &config.Config{})
+	doc := BuildJSONReport(report, time.Time{})
+	if doc.Metrics.Totals.Lines != 45 || doc.Metrics.Totals.Bytes != 900 {
+		t.Errorf("unexpected totals: %+v", doc.Metrics.Totals)
+	}
+	restored := doc.Structure()
+	if stats := restored.Layers[1].Slices[0].Segments[0].Stats["userApi.ts"]; stats.Lines != 30 || stats.Bytes != 600 {
+		t.Errorf("file stats were not restored from JSON: %+v", stats)
+	}
+
+	tree := buildTreemap(report)
+	if tree.Lines != 45 || tree.Files != 3 || len(tree.Children) != 2 {
+		t.Fatalf("unexpected treemap root: %+v", tree)
+	}
+	// userApi.ts импортирует из вышележащего слоя и замыкает цикл
+	entities := tree.Children[1]
+	if entities.ID != "layer:entities" || entities.Lines != 35 || entities.Bytes != 700 || entities.Violations != 2 {
+		t.Errorf("unexpected entities layer: %+v", entities)
+	}
+	slice := entities.Children[0]
+	if slice.Instability != 0.5 || slice.Children[0].Instability != 0.5 || slice.Children[0].Layer != "entities" {
+		t.Errorf("slice metrics must be inherited by segments: %+v", slice)
+	}
+	file := slice.Children[0].Children[0]
+	if file.ID != "file:entities/user/api/userApi.ts" || file.Violations != 2 || file.Lines != 30 {
+		t.Errorf("unexpected file node: %+v", file)
+	}
+}
+
+func TestCIFormatsLocale(t *testing.T) {
+	defer i18n.SetLocale(i18n.DefaultLocale)
+
+	structure := createTestStructureWithDependencies()
+	writers := map[string]func(io.Writer, *model.ProjectStructure, *config.Config) error{
+		"sarif":       WriteSARIF,
+		"junit":       WriteJUnit,
+		"checkstyle":  WriteCheckstyle,
+		"codequality": WriteCodeQuality,
+		"github":      WriteGitHubAnnotations,
+	}
+	check := func(expected, unexpected string) {
+		t.Helper()
+		for name, write := range writers {
+			var buf bytes.Buffer
+			if err := write(&buf, structure, &config.Config{}); err != nil {
+				t.Fatalf("%s failed: %v", name, err)
+			}
+			if output := buf.String(); !strings.Contains(output, expected) || strings.Contains(output, unexpected) {
+				t.Errorf("%s output does not contain %q only:\n%s", name, expected, output)
+			}
+		}
+	}
+
+	// Локаль из окружения не меняет отчеты для CI
+	if err := i18n.SetEnvironmentLocale(i18n.LocaleEn); err != nil {
+		t.Fatal(err)
+	}
+	check("не должен импортировать", "must not import")
+
+	// Явно выбранная локаль (флаг или конфигурация) применяется к ним
+	if err := i18n.SetLocale(i18n.LocaleEn); err != nil {
+		t.Fatal(err)
+	}
+	check("must not import", "не должен импортировать")
+
+	var sarif bytes.Buffer
+	if err := WriteSARIF(&sarif, structure, &config.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sarif.String(), "Import from a higher layer") {
+		t.Errorf("SARIF rule descriptions are not translated:\n%s", sarif.String())
+	}
+}
+
+func TestHTMLLocale(t *testing.T) {
+	if err := i18n.SetLocale(i18n.LocaleEn); err != nil {
+		t.Fatal(err)
+	}
+	defer i18n.SetLocale(i18n.DefaultLocale)
+
+	structure := createTestStructureWithDependencies()
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, NewReport(structure, &config.Config{})); err != nil {
+		t.Fatalf("writeHTML failed: %v", err)
+	}
+	html := buf.String()
+	for _, fragment := range []string{
+		`<html lang="en">`,
+		`Layer: entities`,
+		`(cyclic dependency)`,
+		`"messages":{`,
+		`"showing {0} of {1}"`,
+	} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("HTML report does not contain %s", fragment)
+		}
+	}
+	if strings.Contains(html, "Слой:") {
+		t.Error("HTML report contains untranslated labels")
+	}
+
+	violations := CollectViolations(structure)
+	if len(violations) == 0 || !strings.HasPrefix(violations[0].Message, "Layer ") {
+		t.Errorf("violation messages are not translated: %+v", violations)
+	}
+
+	// Подписи скриптов попадают в window.FSD_REPORT.messages, только если
+	// перечислены в htmlScriptMessages
+	for _, name := range htmlAssetFiles {
+		content, err := htmlAssets.ReadFile("assets/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range regexp.MustCompile(`\bt\('((?:[^'\\]|\\.)*)'`).FindAllStringSubmatch(string(content), -1) {
+			if indexOf(htmlScriptMessages, match[1]) < 0 {
+				t.Errorf("%s: message %q is missing from htmlScriptMessages", name, match[1])
+			}
+		}
+	}
+}
+
+func TestHTMLCustomTemplate(t *testing.T) {
+	dir := t.TempDir()
+	custom := `<html lang="{{locale}}">
+<p id="totals">{{.Metrics.Totals.Files}} {{len .Violations}} {{.GeneratedAt.Year}} {{.Config.SrcDir}}</p>
+<ul>{{range where .Violations "Rule" "fsd/cycle"}}{{template "violation" dict "Violation" . "Root" $.Config.SrcDir}}{{end}}</ul>
+{{range .Dependencies}}{{if allowedCyclical .}}<p class="allowed">{{sliceID .FromLayer .FromSlice}}</p>{{end}}{{end}}
+<p id="edges">{{len (where .Report.Edges "Type" "cyclical")}} {{len .FileGraph.Edges}}</p>
+<script>var metrics = {{json .Metrics.Totals}};</script>
+{{template "source-preview" .}}`
+	partial := `{{define "violation"}}<li>{{pathJoin .Root .Violation.File}}:{{.Violation.Rule}}</li>{{end}}
+{{define "source-preview"}}<div id="custom-preview"></div>{{end}}`
+
+	templatePath := filepath.Join(dir, "custom.html")
+	if err := os.WriteFile(templatePath, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "partials"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "partials", "violation.html"), []byte(partial), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	cfg := &config.Config{
+		SrcDir:                      "src",
+		OutputDir:                   dir,
+		HTMLTemplatePath:            templatePath,
+		AllowedCyclicalDependencies: []string{"app"},
+		HTML:                        config.HTMLConfig{Partials: []string{filepath.Join(dir, "partials", "*.html")}},
+	}
+	structure := createTestStructureWithDependencies()
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, NewReport(structure, cfg)); err != nil {
+		t.Fatalf("writeHTML failed: %v", err)
+	}
+	html := buf.String()
+	for _, fragment := range []string{
+		`<p id="totals">3 6 2023 src</p>`,
+		`<li>src/entities/user/api/userApi.ts:fsd/cycle</li>`,
+		`<p class="allowed">entities/user</p>`,
+		`<p id="edges">1 3</p>`,
+		`var metrics = {"layers":`,
+		`<div id="custom-preview"></div>`,
+	} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("custom template output does not contain %s:\n%s", fragment, html)
+		}
+	}
+
+	cfg.HTML.Partials = []string{filepath.Join(dir, "missing", "*.html")}
+	if err := writeHTML(&bytes.Buffer{}, NewReport(structure, cfg)); err == nil {
+		t.Error("expected error for html.partials without files")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+	path := HistoryPath(dir)
+
+	history, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory without file failed: %v", err)
+	}
+	if len(history.Entries) != 0 {
+		t.Errorf("expected empty history, got %+v", history.Entries)
+	}
+
+	report := NewReport(createTestStructureWithDependencies(), &config.Config{SrcDir: "src", OutputDir: dir})
+	var buf bytes.Buffer
+	if err := writeHTML(&buf, report); err != nil {
+		t.Fatalf("writeHTML failed: %v", err)
+	}
+	if strings.Contains(buf.String(), `id="history-chart"`) {
+		t.Error("HTML report without history contains the trend chart")
+	}
+
+	newer := NewHistoryEntry(report, HistoryCommit{Hash: "bbb", Date: "2024-01-02T10:00:00+03:00", Subject: "second"})
+	older := NewHistoryEntry(report, HistoryCommit{Hash: "aaa", Date: "2024-01-01T10:00:00+03:00", Subject: "first"})
+	if newer.Totals.Violations == 0 || newer.Rules["fsd/cycle"] == 0 {
+		t.Errorf("history entry has no violations: %+v", newer)
+	}
+	if _, ok := newer.Rules["fsd/cross-slice-import"]; !ok {
+		t.Errorf("history entry should list every rule: %+v", newer.Rules)
+	}
+
+	history.Add(newer)
+	history.Add(older)
+	older.Subject = "first (rebuilt)"
+	history.Add(older)
+	if len(history.Entries) != 2 || history.Entries[0].Hash != "aaa" || history.Entries[0].Subject != "first (rebuilt)" {
+		t.Fatalf("unexpected history order: %+v", history.Entries)
+	}
+
+	if err := WriteHistory(path, history); err != nil {
+		t.Fatalf("WriteHistory failed: %v", err)
+	}
+	loaded, err := LoadHistory(path)
+	if err != nil {
+		t.Fatalf("LoadHistory failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, history) {
+		t.Errorf("loaded history differs:\n%+v\n%+v", loaded, history)
+	}
+	if entry, ok := loaded.Lookup("bbb", ""); !ok || entry.Subject != "second" {
+		t.Errorf("Lookup(bbb) = %+v, %v", entry, ok)
+	}
+
+	// Запись, посчитанная с другими параметрами анализа, не используется
+	hash := ConfigHash(report.Config)
+	if hash != ConfigHash(&config.Config{SrcDir: "src", OutputDir: "reports"}) {
+		t.Errorf("output settings must not change the config hash")
+	}
+	if hash == ConfigHash(&config.Config{SrcDir: "src", SegmentRules: []config.SegmentRule{{From: "model", To: "ui"}}}) {
+		t.Errorf("analysis settings must change the config hash")
+	}
+	if _, ok := loaded.Lookup("bbb", hash); ok {
+		t.Errorf("Lookup returned an entry computed with another config")
+	}
+	newer.ConfigHash = hash
+	loaded.Add(newer)
+	if entry, ok := loaded.Lookup("bbb", hash); !ok || entry.ConfigHash != hash {
+		t.Errorf("Lookup(bbb, %s) = %+v, %v", hash, entry, ok)
+	}
+
+	buf.Reset()
+	if err := writeHTML(&buf, report); err != nil {
+		t.Fatalf("writeHTML failed: %v", err)
+	}
+	html := buf.String()
+	for _, fragment := range []string{`id="history-chart"`, `"history":[{"hash":"aaa"`, `FSDTrend`} {
+		if !strings.Contains(html, fragment) {
+			t.Errorf("HTML report does not contain %s", fragment)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeHTML(&bytes.Buffer{}, report); err == nil {
+		t.Error("expected error for corrupted history file")
+	}
+}
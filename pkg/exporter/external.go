@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/i18n"
+)
+
+// externalExporter запускает внешнюю программу, передает ей JSON-отчет
+// (тот же, что и формат json) на stdin и записывает ее stdout в цель вывода.
+type externalExporter struct {
+	name   string
+	config config.ExternalExporter
+}
+
+func (e *externalExporter) Name() string {
+	return e.name
+}
+
+func (e *externalExporter) FileName() string {
+	if e.config.FileName == "" {
+		return Stdout
+	}
+	return e.config.FileName
+}
+
+func (e *externalExporter) Export(w io.Writer, report *Report) error {
+	if e.config.Command == "" {
+		return i18n.Errorf("для внешнего экспортера %s не указана команда", e.name)
+	}
+
+	var input bytes.Buffer
+	if err := writeJSONReport(&input, report); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(e.config.Command, e.config.Args...)
+	cmd.Stdin = &input
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return i18n.Errorf("внешний экспортер %s завершился с ошибкой: %v", e.name, err)
+	}
+
+	return nil
+}
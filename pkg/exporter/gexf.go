@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"io"
+
+	"fsd-crawler/pkg/model"
+)
+
+const gexfNamespace = "http://gexf.net/1.3"
+
+type gexfDocument struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	PID       string         `xml:"pid,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Label     string         `xml:"label,attr"`
+	Weight    int            `xml:"weight,attr"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfExporter struct{}
+
+func init() {
+	Register(gexfExporter{})
+}
+
+func (gexfExporter) Name() string {
+	return "gexf"
+}
+
+func (gexfExporter) FileName() string {
+	return "fsd_structure.gexf"
+}
+
+func (gexfExporter) Export(w io.Writer, report *Report) error {
+	return writeGEXF(w, buildHierarchyGraph(report))
+}
+
+// WriteGEXF записывает граф зависимостей в формате GEXF 1.3 (Gephi).
+// Иерархия задается атрибутом pid, вес ребра — количество импортов.
+func WriteGEXF(w io.Writer, structure *model.ProjectStructure, fileLevel bool) error {
+	return writeGEXF(w, buildHierarchyGraph(graphReport(structure, fileLevel)))
+}
+
+func writeGEXF(w io.Writer, g *hierarchyGraph) error {
+	doc := gexfDocument{
+		Xmlns:   gexfNamespace,
+		Version: "1.3",
+		Meta: gexfMeta{
+			Creator:     toolName,
+			Description: "Feature-Sliced Design dependency graph",
+		},
+		Graph: gexfGraph{
+			DefaultEdgeType: "directed",
+			Mode:            "static",
+			Attributes: []gexfAttributes{
+				{Class: "node", Attributes: []gexfAttribute{
+					{ID: "kind", Title: "kind", Type: "string"},
+					{ID: "path", Title: "path", Type: "string"},
+				}},
+				{Class: "edge", Attributes: []gexfAttribute{
+					{ID: "type", Title: "type", Type: "string"},
+					{ID: "importKind", Title: "importKind", Type: "string"},
+				}},
+			},
+		},
+	}
+
+	for _, node := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    node.ID,
+			Label: node.Name,
+			PID:   node.Parent,
+			AttValues: []gexfAttValue{
+				{For: "kind", Value: node.Kind},
+				{For: "path", Value: node.Path},
+			},
+		})
+	}
+
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:     edge.ID,
+			Source: edge.Source,
+			Target: edge.Target,
+			Label:  edge.Type,
+			Weight: edge.Weight,
+			AttValues: []gexfAttValue{
+				{For: "type", Value: edge.Type},
+				{For: "importKind", Value: edge.ImportKind},
+			},
+		})
+	}
+
+	return writeXML(w, doc)
+}
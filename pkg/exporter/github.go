@@ -0,0 +1,59 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/model"
+)
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// githubExporter по умолчанию пишет в стандартный вывод: GitHub Actions
+// превращает команды ::error в аннотации в диффе pull request.
+type githubExporter struct{}
+
+func init() {
+	Register(githubExporter{})
+}
+
+func (githubExporter) Name() string {
+	return "github"
+}
+
+func (githubExporter) FileName() string {
+	return Stdout
+}
+
+func (githubExporter) Export(w io.Writer, report *Report) error {
+	return WriteGitHubAnnotations(w, report.Structure, report.Config)
+}
+
+// WriteGitHubAnnotations записывает по одной команде ::error или ::warning на
+// каждое нарушение.
+func WriteGitHubAnnotations(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	out := bufio.NewWriter(w)
+
+	for _, violation := range CollectViolations(structure) {
+		command := "error"
+		if ruleLevel(violation.RuleID) != "error" {
+			command = "warning"
+		}
+
+		properties := []string{"file=" + githubPropertyEscaper.Replace(sourcePath(cfg, violation.File))}
+		if violation.Line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", violation.Line))
+		}
+		properties = append(properties, "title="+githubPropertyEscaper.Replace(violation.RuleID))
+
+		fmt.Fprintf(out, "::%s %s::%s\n", command, strings.Join(properties, ","), githubDataEscaper.Replace(violation.ReportMessage))
+	}
+
+	return out.Flush()
+}
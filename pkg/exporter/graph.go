@@ -0,0 +1,367 @@
+package exporter
+
+import (
+	"path"
+	"sort"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	GraphDetailSlice = "slice"
+	GraphDetailFile  = "file"
+)
+
+const (
+	defaultDiagramMaxNodes = 100
+	defaultDiagramMaxEdges = 200
+)
+
+type graphSlice struct {
+	ID    string
+	Name  string
+	Files []string
+}
+
+type graphLayer struct {
+	Name   string
+	Slices []*graphSlice
+}
+
+type graphEdge struct {
+	From    string
+	To      string
+	Type    dependencies.DependencyType
+	Imports []dependencies.Dependency
+}
+
+// dependencyGraph — общий для текстовых форматов (DOT и т.п.) граф
+// зависимостей: слои в порядке model.KnownLayers, внутри слоев слайсы,
+// а при файловой детализации — файлы слайсов.
+type dependencyGraph struct {
+	Layers    []*graphLayer
+	Edges     []graphEdge
+	FileLevel bool
+}
+
+func sliceID(layerName, sliceName string) string {
+	if sliceName == "" || sliceName == layerName {
+		return layerName
+	}
+	return layerName + "/" + sliceName
+}
+
+func fileLevelGraph(cfg *config.Config) bool {
+	return cfg != nil && cfg.GraphDetail == GraphDetailFile
+}
+
+func buildDependencyGraph(structure *model.ProjectStructure, fileLevel bool) *dependencyGraph {
+	g := &dependencyGraph{FileLevel: fileLevel}
+
+	layers := make(map[string]*graphLayer)
+	slices := make(map[string]*graphSlice)
+	files := make(map[string]bool)
+
+	addSlice := func(layerName, sliceName string) *graphSlice {
+		layer, ok := layers[layerName]
+		if !ok {
+			layer = &graphLayer{Name: layerName}
+			layers[layerName] = layer
+			g.Layers = append(g.Layers, layer)
+		}
+
+		id := sliceID(layerName, sliceName)
+		slice, ok := slices[id]
+		if !ok {
+			name := sliceName
+			if name == "" {
+				name = layerName
+			}
+			slice = &graphSlice{ID: id, Name: name}
+			slices[id] = slice
+			layer.Slices = append(layer.Slices, slice)
+		}
+		return slice
+	}
+
+	addFile := func(slice *graphSlice, file string) {
+		if file == "" || files[file] {
+			return
+		}
+		files[file] = true
+		slice.Files = append(slice.Files, file)
+	}
+
+	for _, layer := range structure.Layers {
+		for _, s := range layer.Slices {
+			slice := addSlice(layer.Name, s.Name)
+			for _, segment := range s.Segments {
+				for _, file := range segment.Files {
+					dir := slice.ID
+					if segment.Name != "root" {
+						dir = path.Join(dir, segment.Name)
+					}
+					addFile(slice, path.Join(dir, file))
+				}
+			}
+		}
+	}
+
+	deps := structureDependencies(structure)
+
+	edges := make(map[string]*graphEdge)
+	var keys []string
+	for _, dep := range deps {
+		fromSlice := addSlice(dep.FromLayer, dep.FromSlice)
+		toSlice := addSlice(dep.ToLayer, dep.ToSlice)
+
+		from, to := fromSlice.ID, toSlice.ID
+		if fileLevel {
+			if dep.FromFile == "" || dep.ToFile == "" {
+				continue
+			}
+			addFile(fromSlice, dep.FromFile)
+			addFile(toSlice, dep.ToFile)
+			from, to = dep.FromFile, dep.ToFile
+		}
+		if from == to {
+			continue
+		}
+
+		key := from + "\x00" + to + "\x00" + string(dep.Type)
+		edge, ok := edges[key]
+		if !ok {
+			edge = &graphEdge{From: from, To: to, Type: dep.Type}
+			edges[key] = edge
+			keys = append(keys, key)
+		}
+		edge.Imports = append(edge.Imports, dep)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		g.Edges = append(g.Edges, *edges[key])
+	}
+
+	sort.SliceStable(g.Layers, func(i, j int) bool {
+		return layerOrder(g.Layers[i].Name) < layerOrder(g.Layers[j].Name)
+	})
+
+	for _, layer := range g.Layers {
+		sort.SliceStable(layer.Slices, func(i, j int) bool {
+			return layer.Slices[i].ID < layer.Slices[j].ID
+		})
+		for _, slice := range layer.Slices {
+			sort.Strings(slice.Files)
+		}
+	}
+
+	return g
+}
+
+func layerOrder(layerName string) int {
+	for i, known := range model.KnownLayers {
+		if known == layerName {
+			return i
+		}
+	}
+	return len(model.KnownLayers)
+}
+
+// IsViolation сообщает, нарушает ли ребро правила FSD: импорт из
+// нижележащего слоя или импорт между разными слайсами одного слоя.
+func (e graphEdge) IsViolation() bool {
+	switch e.Type {
+	case dependencies.DependencyCyclical:
+		return true
+	case dependencies.DependencySameLayer:
+		for _, dep := range e.Imports {
+			if sliceID(dep.FromLayer, dep.FromSlice) != sliceID(dep.ToLayer, dep.ToSlice) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nodeIDs возвращает узлы графа (слайсы или файлы) в порядке слоев.
+func (g *dependencyGraph) nodeIDs() []string {
+	var nodes []string
+	for _, layer := range g.Layers {
+		for _, slice := range layer.Slices {
+			if g.FileLevel {
+				nodes = append(nodes, slice.Files...)
+			} else {
+				nodes = append(nodes, slice.ID)
+			}
+		}
+	}
+	return nodes
+}
+
+// keepNodes оставляет в графе только перечисленные узлы и ребра между ними;
+// опустевшие слайсы и слои удаляются.
+func (g *dependencyGraph) keepNodes(keep map[string]bool) {
+	var layers []*graphLayer
+	for _, layer := range g.Layers {
+		var slices []*graphSlice
+		for _, slice := range layer.Slices {
+			if g.FileLevel {
+				var files []string
+				for _, file := range slice.Files {
+					if keep[file] {
+						files = append(files, file)
+					}
+				}
+				slice.Files = files
+				if len(files) == 0 {
+					continue
+				}
+			} else if !keep[slice.ID] {
+				continue
+			}
+			slices = append(slices, slice)
+		}
+		layer.Slices = slices
+		if len(slices) > 0 {
+			layers = append(layers, layer)
+		}
+	}
+	g.Layers = layers
+
+	var edges []graphEdge
+	for _, edge := range g.Edges {
+		if keep[edge.From] && keep[edge.To] {
+			edges = append(edges, edge)
+		}
+	}
+	g.Edges = edges
+}
+
+// applyDiagramLimits фильтрует граф по настройкам diagram и ограничивает его
+// размер: при превышении maxEdges в первую очередь остаются нарушения и ребра
+// с наибольшим числом импортов, при превышении maxNodes — узлы с наибольшим
+// числом связей. Возвращает количество скрытых узлов и ребер.
+func (g *dependencyGraph) applyDiagramLimits(opts config.DiagramConfig) (int, int) {
+	totalNodes, totalEdges := len(g.nodeIDs()), len(g.Edges)
+
+	keep := make(map[string]bool)
+	for _, layer := range g.Layers {
+		if len(opts.Layers) > 0 && !containsString(opts.Layers, layer.Name) {
+			continue
+		}
+		for _, slice := range layer.Slices {
+			if matchesAny(opts.Exclude, slice.ID) {
+				continue
+			}
+			if !g.FileLevel {
+				keep[slice.ID] = true
+				continue
+			}
+			for _, file := range slice.Files {
+				if !matchesAny(opts.Exclude, file) {
+					keep[file] = true
+				}
+			}
+		}
+	}
+	g.keepNodes(keep)
+
+	if opts.ViolationsOnly {
+		keep = make(map[string]bool)
+		for _, edge := range g.Edges {
+			if edge.IsViolation() {
+				keep[edge.From] = true
+				keep[edge.To] = true
+			}
+		}
+		g.keepNodes(keep)
+
+		var violations []graphEdge
+		for _, edge := range g.Edges {
+			if edge.IsViolation() {
+				violations = append(violations, edge)
+			}
+		}
+		g.Edges = violations
+	}
+
+	maxEdges := opts.MaxEdges
+	if maxEdges == 0 {
+		maxEdges = defaultDiagramMaxEdges
+	}
+	if maxEdges > 0 && len(g.Edges) > maxEdges {
+		ranked := append([]graphEdge{}, g.Edges...)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			if ranked[i].IsViolation() != ranked[j].IsViolation() {
+				return ranked[i].IsViolation()
+			}
+			return len(ranked[i].Imports) > len(ranked[j].Imports)
+		})
+
+		kept := make(map[string]bool)
+		for _, edge := range ranked[:maxEdges] {
+			kept[edge.From+"\x00"+edge.To+"\x00"+string(edge.Type)] = true
+		}
+
+		var edges []graphEdge
+		for _, edge := range g.Edges {
+			if kept[edge.From+"\x00"+edge.To+"\x00"+string(edge.Type)] {
+				edges = append(edges, edge)
+			}
+		}
+		g.Edges = edges
+	}
+
+	maxNodes := opts.MaxNodes
+	if maxNodes == 0 {
+		maxNodes = defaultDiagramMaxNodes
+	}
+	if nodes := g.nodeIDs(); maxNodes > 0 && len(nodes) > maxNodes {
+		degree := make(map[string]int)
+		violating := make(map[string]bool)
+		for _, edge := range g.Edges {
+			degree[edge.From] += len(edge.Imports)
+			degree[edge.To] += len(edge.Imports)
+			if edge.IsViolation() {
+				violating[edge.From] = true
+				violating[edge.To] = true
+			}
+		}
+
+		sort.SliceStable(nodes, func(i, j int) bool {
+			if violating[nodes[i]] != violating[nodes[j]] {
+				return violating[nodes[i]]
+			}
+			return degree[nodes[i]] > degree[nodes[j]]
+		})
+
+		keep = make(map[string]bool)
+		for _, node := range nodes[:maxNodes] {
+			keep[node] = true
+		}
+		g.keepNodes(keep)
+	}
+
+	return totalNodes - len(g.nodeIDs()), totalEdges - len(g.Edges)
+}
+
+func containsString(items []string, item string) bool {
+	for _, existing := range items {
+		if existing == item {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
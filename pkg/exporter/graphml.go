@@ -0,0 +1,133 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"fsd-crawler/pkg/model"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID      string `xml:"id,attr"`
+	For     string `xml:"for,attr"`
+	Name    string `xml:"attr.name,attr"`
+	Type    string `xml:"attr.type,attr"`
+	Default string `xml:"default,omitempty"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID    string        `xml:"id,attr"`
+	Data  []graphMLData `xml:"data"`
+	Graph *graphMLGraph `xml:"graph,omitempty"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLKeys = []graphMLKey{
+	{ID: "kind", For: "node", Name: "kind", Type: "string"},
+	{ID: "label", For: "node", Name: "label", Type: "string"},
+	{ID: "path", For: "node", Name: "path", Type: "string"},
+	{ID: "parent", For: "node", Name: "parent", Type: "string"},
+	{ID: "type", For: "edge", Name: "type", Type: "string"},
+	{ID: "importKind", For: "edge", Name: "importKind", Type: "string"},
+	{ID: "weight", For: "edge", Name: "weight", Type: "int", Default: "1"},
+}
+
+type graphMLExporter struct{}
+
+func init() {
+	Register(graphMLExporter{})
+}
+
+func (graphMLExporter) Name() string {
+	return "graphml"
+}
+
+func (graphMLExporter) FileName() string {
+	return "fsd_structure.graphml"
+}
+
+func (graphMLExporter) Export(w io.Writer, report *Report) error {
+	return writeGraphML(w, buildHierarchyGraph(report))
+}
+
+// WriteGraphML записывает граф зависимостей в формате GraphML. Иерархия
+// слой → слайс → сегмент → файл передается вложенными графами и атрибутом
+// parent для инструментов, которые не поддерживают вложенность.
+func WriteGraphML(w io.Writer, structure *model.ProjectStructure, fileLevel bool) error {
+	return writeGraphML(w, buildHierarchyGraph(graphReport(structure, fileLevel)))
+}
+
+func writeGraphML(w io.Writer, g *hierarchyGraph) error {
+	children := g.children()
+
+	var nest func(parent string) []graphMLNode
+	nest = func(parent string) []graphMLNode {
+		var nodes []graphMLNode
+		for _, node := range children[parent] {
+			graphNode := graphMLNode{
+				ID: node.ID,
+				Data: []graphMLData{
+					{Key: "kind", Value: node.Kind},
+					{Key: "label", Value: node.Name},
+					{Key: "path", Value: node.Path},
+				},
+			}
+			if node.Parent != "" {
+				graphNode.Data = append(graphNode.Data, graphMLData{Key: "parent", Value: node.Parent})
+			}
+			if nested := nest(node.ID); len(nested) > 0 {
+				graphNode.Graph = &graphMLGraph{ID: node.ID + ":", EdgeDefault: "directed", Nodes: nested}
+			}
+			nodes = append(nodes, graphNode)
+		}
+		return nodes
+	}
+
+	doc := graphMLDocument{
+		Xmlns: graphMLNamespace,
+		Keys:  graphMLKeys,
+		Graph: graphMLGraph{ID: "fsd", EdgeDefault: "directed", Nodes: nest("")},
+	}
+	for _, edge := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     edge.ID,
+			Source: edge.Source,
+			Target: edge.Target,
+			Data: []graphMLData{
+				{Key: "type", Value: edge.Type},
+				{Key: "importKind", Value: edge.ImportKind},
+				{Key: "weight", Value: strconv.Itoa(edge.Weight)},
+			},
+		})
+	}
+
+	return writeXML(w, doc)
+}
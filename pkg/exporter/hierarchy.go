@@ -0,0 +1,113 @@
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/model"
+)
+
+// hierarchyEdge — ребро графа для внешних инструментов анализа графов.
+// Импорты одного вида и типа между одной парой узлов объединяются, Weight —
+// их количество.
+type hierarchyEdge struct {
+	ID         string
+	Source     string
+	Target     string
+	Type       string
+	ImportKind string
+	Weight     int
+}
+
+// hierarchyGraph — граф с полной иерархией слой → слайс → сегмент → файл.
+// Ребра соединяют слайсы, а при graphDetail: file — файлы.
+type hierarchyGraph struct {
+	Nodes []ReportNode
+	Edges []hierarchyEdge
+}
+
+// graphReport готовит отчет для публичных функций Write*, которые вместо
+// конфигурации принимают только детализацию графа.
+func graphReport(structure *model.ProjectStructure, fileLevel bool) *Report {
+	cfg := &config.Config{GraphDetail: GraphDetailSlice}
+	if fileLevel {
+		cfg.GraphDetail = GraphDetailFile
+	}
+	return NewReport(structure, cfg)
+}
+
+func buildHierarchyGraph(report *Report) *hierarchyGraph {
+	g := &hierarchyGraph{Nodes: buildReportNodes(report.Structure)}
+	fileLevel := fileLevelGraph(report.Config)
+
+	nodes := make(map[string]bool)
+	for _, node := range g.Nodes {
+		nodes[node.ID] = true
+	}
+
+	// Слайсы, которые есть только в зависимостях, добавляются в конец
+	ensureSlice := func(layerName, sliceName string) string {
+		id := nodeID(NodeSlice, sliceID(layerName, sliceName))
+		if nodes[id] {
+			return id
+		}
+		layerID := nodeID(NodeLayer, layerName)
+		if !nodes[layerID] {
+			nodes[layerID] = true
+			g.Nodes = append(g.Nodes, ReportNode{ID: layerID, Kind: NodeLayer, Name: layerName, Path: layerName})
+		}
+		name := sliceName
+		if name == "" {
+			name = layerName
+		}
+		nodes[id] = true
+		g.Nodes = append(g.Nodes, ReportNode{ID: id, Kind: NodeSlice, Name: name, Path: sliceID(layerName, sliceName), Parent: layerID})
+		return id
+	}
+
+	endpoint := func(sliceNode, file string) string {
+		if fileLevel && file != "" && nodes[nodeID(NodeFile, file)] {
+			return nodeID(NodeFile, file)
+		}
+		return sliceNode
+	}
+
+	edges := make(map[string]*hierarchyEdge)
+	var keys []string
+	for _, dep := range report.Dependencies {
+		source := endpoint(ensureSlice(dep.FromLayer, dep.FromSlice), dep.FromFile)
+		target := endpoint(ensureSlice(dep.ToLayer, dep.ToSlice), dep.ToFile)
+		if source == target {
+			continue
+		}
+
+		key := strings.Join([]string{source, target, string(dep.Type), string(dep.ImportKind)}, "\x00")
+		edge, ok := edges[key]
+		if !ok {
+			edge = &hierarchyEdge{Source: source, Target: target, Type: string(dep.Type), ImportKind: string(dep.ImportKind)}
+			edges[key] = edge
+			keys = append(keys, key)
+		}
+		edge.Weight++
+	}
+
+	sort.Strings(keys)
+	for i, key := range keys {
+		edge := edges[key]
+		edge.ID = fmt.Sprintf("e%d", i)
+		g.Edges = append(g.Edges, *edge)
+	}
+
+	return g
+}
+
+// children группирует узлы по родителю, сохраняя порядок обхода.
+func (g *hierarchyGraph) children() map[string][]ReportNode {
+	children := make(map[string][]ReportNode)
+	for _, node := range g.Nodes {
+		children[node.Parent] = append(children[node.Parent], node)
+	}
+	return children
+}
@@ -0,0 +1,162 @@
+package exporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/i18n"
+)
+
+// HistoryFileName — файл в outputDir, в котором команда history хранит
+// метрики архитектуры по коммитам. HTML-отчет строит по нему график динамики.
+const HistoryFileName = "fsd_history.json"
+
+// HistorySchemaVersion — версия формата файла истории.
+const HistorySchemaVersion = "1.0.0"
+
+// History — метрики архитектуры по коммитам, от старых к новым.
+type History struct {
+	SchemaVersion string         `json:"schemaVersion"`
+	Entries       []HistoryEntry `json:"entries"`
+}
+
+// HistoryCommit — коммит, для которого посчитаны метрики. Date — дата
+// коммита (committer date) в формате RFC 3339.
+type HistoryCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+// HistoryEntry — итоговые метрики и число нарушений каждого правила на
+// одном коммите. ConfigHash — отпечаток параметров анализа (ConfigHash),
+// с которыми посчитана запись.
+type HistoryEntry struct {
+	HistoryCommit
+	ConfigHash string         `json:"configHash,omitempty"`
+	Totals     ReportTotals   `json:"totals"`
+	Rules      map[string]int `json:"rules"`
+}
+
+// ConfigHash возвращает отпечаток параметров анализа — тех же, что
+// сохраняются в metadata.config JSON-отчета. Настройки вывода на него не
+// влияют.
+func ConfigHash(cfg *config.Config) string {
+	data, _ := json.Marshal(buildReportConfig(cfg))
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// NewHistoryEntry считает метрики отчета для записи истории.
+func NewHistoryEntry(report *Report, commit HistoryCommit) HistoryEntry {
+	doc := BuildJSONReport(report, time.Time{})
+	entry := HistoryEntry{
+		HistoryCommit: commit,
+		Totals:        doc.Metrics.Totals,
+		Rules:         make(map[string]int),
+	}
+	for _, rule := range violationRules {
+		entry.Rules[rule.ID] = 0
+	}
+	for _, violation := range doc.Violations {
+		entry.Rules[violation.Rule]++
+	}
+	return entry
+}
+
+// Lookup возвращает запись истории для коммита, посчитанную с параметрами
+// анализа configHash. Записи с другими параметрами (или без отпечатка, из
+// старых версий) не возвращаются, чтобы их пересчитали.
+func (h *History) Lookup(hash, configHash string) (HistoryEntry, bool) {
+	for _, entry := range h.Entries {
+		if entry.Hash == hash && entry.ConfigHash == configHash {
+			return entry, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// Add добавляет или заменяет запись коммита и сохраняет порядок записей по
+// дате коммита.
+func (h *History) Add(entry HistoryEntry) {
+	replaced := false
+	for i := range h.Entries {
+		if h.Entries[i].Hash == entry.Hash {
+			h.Entries[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		h.Entries = append(h.Entries, entry)
+	}
+
+	sort.SliceStable(h.Entries, func(i, j int) bool {
+		a, _ := time.Parse(time.RFC3339, h.Entries[i].Date)
+		b, _ := time.Parse(time.RFC3339, h.Entries[j].Date)
+		return a.Before(b)
+	})
+}
+
+// HistoryPath возвращает путь к файлу истории в директории отчетов.
+func HistoryPath(outputDir string) string {
+	return filepath.Join(outputDir, HistoryFileName)
+}
+
+// LoadHistory читает файл истории. Если файла нет, возвращается пустая
+// история.
+func LoadHistory(path string) (*History, error) {
+	history := &History{SchemaVersion: HistorySchemaVersion, Entries: []HistoryEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, i18n.Errorf("не удалось прочитать историю %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, i18n.Errorf("не удалось разобрать историю %s: %v", path, err)
+	}
+	if history.SchemaVersion != HistorySchemaVersion {
+		return nil, i18n.Errorf("неподдерживаемая версия истории %q (ожидается %s)", history.SchemaVersion, HistorySchemaVersion)
+	}
+	return history, nil
+}
+
+// WriteHistory сохраняет историю, создавая директорию отчетов при
+// необходимости.
+func WriteHistory(path string, history *History) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return i18n.Errorf("не удалось создать директорию для вывода: %v", err)
+	}
+
+	history.SchemaVersion = HistorySchemaVersion
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return i18n.Errorf("ошибка при кодировании в JSON: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return i18n.Errorf("не удалось записать историю %s: %v", path, err)
+	}
+	return nil
+}
+
+// reportHistory загружает историю из outputDir для графика динамики в
+// HTML-отчете.
+func reportHistory(report *Report) ([]HistoryEntry, error) {
+	if report.Config == nil || report.Config.OutputDir == "" {
+		return nil, nil
+	}
+	history, err := LoadHistory(HistoryPath(report.Config.OutputDir))
+	if err != nil {
+		return nil, err
+	}
+	return history.Entries, nil
+}
@@ -1,590 +1,577 @@
 package exporter
 
 import (
+	"crypto/sha256"
+	"embed"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
-	"path/filepath"
+	"path"
+	"strings"
 
 	"fsd-crawler/pkg/config"
 	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
 	"fsd-crawler/pkg/model"
 )
 
-func GenerateHTML(structure *model.ProjectStructure, cfg *config.Config) error {
-	var tmplContent string
-	var err error
+const (
+	HTMLAssetsInline   = "inline"
+	HTMLAssetsExternal = "external"
+)
 
-	if cfg != nil && cfg.HTMLTemplatePath != "" {
-		tmplBytes, err := os.ReadFile(cfg.HTMLTemplatePath)
+const (
+	HTMLLayoutLayered = "layered"
+	HTMLLayoutForce   = "force"
+)
+
+// htmlAssetDir — директория рядом с HTML-отчетом, куда при
+// html.assets: external сохраняются стили и скрипты.
+const htmlAssetDir = "assets"
+
+// htmlAssetFiles — стили и скрипты отчета в порядке подключения.
+var htmlAssetFiles = []string{"report.css", "graph.js", "treemap.js", "history.js", "report.js"}
+
+//go:embed assets
+var htmlAssets embed.FS
+
+// htmlScriptMessages — подписи, которые формируют скрипты отчета. Их
+// переводы передаются в window.FSD_REPORT.messages, {0}, {1}... в подписи
+// заменяются аргументами.
+var htmlScriptMessages = []string{
+	" (свернутый слой, слайсов: {0})",
+	" (разрешены циклические зависимости)",
+	"показано {0} из {1}",
+	"развернуть",
+	"свернуть",
+	"{0} → {1}: найдено путей: {2}",
+	"{0} не зависит от {1}",
+	"{0} → {1}: импортов: {2}",
+	"строк: {0}, байт: {1}, файлов: {2}",
+	"нарушений: {0}",
+	"нестабильность слайса: {0}",
+	"файл не встроен в отчет (см. html.sourceMaxBytes)",
+	"сервер вернул {0}",
+	"Исходный код недоступен: {0}",
+	"на уровень выше",
+	"Нет данных",
+	"всего нарушений",
+}
+
+type htmlExporter struct{}
+
+func init() {
+	Register(htmlExporter{})
+}
+
+func (htmlExporter) Name() string {
+	return "html"
+}
+
+func (htmlExporter) FileName() string {
+	return "fsd_structure.html"
+}
+
+func (htmlExporter) Export(w io.Writer, report *Report) error {
+	return writeHTML(w, report)
+}
+
+// Assets возвращает файлы стилей и скриптов, если они подключаются к отчету
+// отдельными файлами (html.assets: external).
+func (htmlExporter) Assets(report *Report) (map[string][]byte, error) {
+	if htmlAssetsMode(report.Config) != HTMLAssetsExternal {
+		return nil, nil
+	}
+
+	files := make(map[string][]byte)
+	for _, name := range htmlAssetFiles {
+		content, err := htmlAssets.ReadFile(path.Join("assets", name))
 		if err != nil {
-			return fmt.Errorf("не удалось прочитать пользовательский HTML шаблон: %v", err)
+			return nil, err
 		}
-		tmplContent = string(tmplBytes)
-	} else {
-		tmplContent = defaultHTMLTemplate
+		files[path.Join(htmlAssetDir, name)] = content
+	}
+	return files, nil
+}
+
+func GenerateHTML(structure *model.ProjectStructure, cfg *config.Config) error {
+	_, err := Run("html", structure, cfg)
+	return err
+}
+
+func htmlLayoutMode(cfg *config.Config) (string, error) {
+	if cfg == nil || cfg.HTML.Layout == "" {
+		return HTMLLayoutLayered, nil
+	}
+	switch cfg.HTML.Layout {
+	case HTMLLayoutLayered, HTMLLayoutForce:
+		return cfg.HTML.Layout, nil
 	}
+	return "", i18n.Errorf("неизвестная раскладка html.layout %q (ожидается layered или force)", cfg.HTML.Layout)
+}
 
-	outputDir := "./dist"
-	if cfg != nil && cfg.OutputDir != "" {
-		outputDir = cfg.OutputDir
+func htmlAssetsMode(cfg *config.Config) string {
+	if cfg == nil || cfg.HTML.Assets == "" {
+		return HTMLAssetsInline
 	}
+	return cfg.HTML.Assets
+}
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("не удалось создать директорию для вывода: %v", err)
+// HTMLAssets — подключение стилей и скриптов отчета: встроенные в страницу
+// теги <style> и <script> или ссылки на файлы в директории assets.
+type HTMLAssets struct {
+	Styles  template.HTML
+	Scripts template.HTML
+}
+
+func buildHTMLAssets(cfg *config.Config) (HTMLAssets, error) {
+	var assets HTMLAssets
+	mode := htmlAssetsMode(cfg)
+	if mode != HTMLAssetsInline && mode != HTMLAssetsExternal {
+		return assets, i18n.Errorf("неизвестный режим html.assets %q (ожидается inline или external)", mode)
 	}
 
-	outputPath := filepath.Join(outputDir, "fsd_structure.html")
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("не удалось создать HTML файл: %v", err)
+	var styles, scripts strings.Builder
+	for _, name := range htmlAssetFiles {
+		content, err := htmlAssets.ReadFile(path.Join("assets", name))
+		if err != nil {
+			return assets, i18n.Errorf("не удалось прочитать ресурс отчета %s: %v", name, err)
+		}
+
+		isStyle := path.Ext(name) == ".css"
+		if mode == HTMLAssetsExternal {
+			// Хеш содержимого в ссылке сбрасывает кеш браузера при обновлении
+			hash := sha256.Sum256(content)
+			href := fmt.Sprintf("%s/%s?v=%x", htmlAssetDir, name, hash[:6])
+			if isStyle {
+				fmt.Fprintf(&styles, "<link rel=\"stylesheet\" href=\"%s\">\n", href)
+			} else {
+				fmt.Fprintf(&scripts, "<script src=\"%s\"></script>\n", href)
+			}
+			continue
+		}
+
+		if isStyle {
+			fmt.Fprintf(&styles, "<style>\n%s</style>\n", content)
+		} else {
+			fmt.Fprintf(&scripts, "<script>\n%s</script>\n", content)
+		}
+	}
+
+	assets.Styles = template.HTML(styles.String())
+	assets.Scripts = template.HTML(scripts.String())
+	return assets, nil
+}
+
+// htmlDependency — зависимость в данных window.FSD_REPORT для скриптов отчета.
+// AllowedCyclical — циклическая зависимость разрешена настройкой
+// allowedCyclicalDependencies и показывается как нормальная.
+type htmlDependency struct {
+	Source          string `json:"source"`
+	Target          string `json:"target"`
+	Type            string `json:"type"`
+	FromLayer       string `json:"fromLayer"`
+	FromSlice       string `json:"fromSlice"`
+	ToLayer         string `json:"toLayer"`
+	ToSlice         string `json:"toSlice"`
+	FromFile        string `json:"fromFile"`
+	ToFile          string `json:"toFile"`
+	Line            int    `json:"line"`
+	ImportPath      string `json:"importPath"`
+	AllowedCyclical bool   `json:"allowedCyclical"`
+}
+
+// htmlReportData — данные window.FSD_REPORT. InternalImports — импорты
+// внутри слайсов для поиска путей между файлами. Layout — послойная раскладка
+// графа (порядок слайсов подбирается только для LayoutMode layered),
+// LayoutMode — раскладка, которая показывается при открытии отчета.
+// Sources — встроенный исходный код для предпросмотра мест импорта,
+// SourceURL — адрес локального сервера, отдающего остальные файлы.
+// Tree — иерархия с объемом кода для treemap и sunburst. History — метрики
+// по коммитам из файла истории для графика динамики. Messages — переводы
+// подписей скриптов отчета на текущую локаль.
+type htmlReportData struct {
+	AllowedCyclicalDependencies []string          `json:"allowedCyclicalDependencies"`
+	Dependencies                []htmlDependency  `json:"dependencies"`
+	InternalImports             []htmlDependency  `json:"internalImports"`
+	Layout                      *graphLayout      `json:"layout"`
+	LayoutMode                  string            `json:"layoutMode"`
+	Sources                     map[string]string `json:"sources"`
+	SourceURL                   string            `json:"sourceURL"`
+	Tree                        *treemapNode      `json:"tree"`
+	History                     []HistoryEntry    `json:"history"`
+	Messages                    map[string]string `json:"messages"`
+}
+
+func buildHTMLReportData(report *Report, allowedCyclical []string, layoutMode string) htmlReportData {
+	data := htmlReportData{
+		AllowedCyclicalDependencies: emptyIfNil(allowedCyclical),
+		Dependencies:                buildHTMLDependencies(report, report.Dependencies),
+		InternalImports:             buildHTMLDependencies(report, report.Structure.InternalImports),
+		Layout:                      buildLayeredLayout(report.Structure, layoutMode == HTMLLayoutLayered),
+		LayoutMode:                  layoutMode,
+		Sources:                     collectSources(report),
+		Tree:                        buildTreemap(report),
+		Messages:                    i18n.Messages(htmlScriptMessages),
+	}
+	if report.Config != nil && report.Config.ServeHTML {
+		data.SourceURL = strings.TrimPrefix(HTMLSourceRoute, "/")
 	}
-	defer outputFile.Close()
-
-	templateData := struct {
-		Layers                    []*model.FSDLayer
-		Dependencies              []dependencies.Dependency
-		HasDependencies           bool
-		AllowedCyclicalDependencies []string
-	}{
-		Layers:                    structure.Layers,
-		Dependencies:              []dependencies.Dependency{},
-		HasDependencies:           len(structure.Dependencies) > 0,
-		AllowedCyclicalDependencies: cfg.AllowedCyclicalDependencies,
+	return data
+}
+
+func buildHTMLDependencies(report *Report, deps []dependencies.Dependency) []htmlDependency {
+	var allowed []string
+	if report.Config != nil {
+		allowed = report.Config.AllowedCyclicalDependencies
+	}
+
+	// Порядок как в JSON-отчете, чтобы render давал те же данные, что и анализ
+	result := make([]htmlDependency, 0, len(deps))
+	for _, dep := range sortedDependencies(deps) {
+		result = append(result, htmlDependency{
+			Source:          sliceID(dep.FromLayer, dep.FromSlice),
+			Target:          sliceID(dep.ToLayer, dep.ToSlice),
+			Type:            string(dep.Type),
+			FromLayer:       dep.FromLayer,
+			FromSlice:       dep.FromSlice,
+			ToLayer:         dep.ToLayer,
+			ToSlice:         dep.ToSlice,
+			FromFile:        dep.FromFile,
+			ToFile:          dep.ToFile,
+			Line:            dep.Line,
+			ImportPath:      dep.ImportPath,
+			AllowedCyclical: isAllowedCyclical(allowed, dep),
+		})
 	}
+	return result
+}
+
+func writeHTML(w io.Writer, report *Report) error {
+	cfg := report.Config
 
-	for _, dep := range structure.Dependencies {
-		if d, ok := dep.(dependencies.Dependency); ok {
-			templateData.Dependencies = append(templateData.Dependencies, d)
+	tmplContent := defaultHTMLTemplate
+	if cfg != nil && cfg.HTMLTemplatePath != "" {
+		tmplBytes, err := os.ReadFile(cfg.HTMLTemplatePath)
+		if err != nil {
+			return i18n.Errorf("не удалось прочитать пользовательский HTML шаблон: %v", err)
 		}
+		tmplContent = string(tmplBytes)
+	}
+
+	templateData, err := buildHTMLTemplateData(report)
+	if err != nil {
+		return err
 	}
 
-	t, err := template.New("fsdStructure").Parse(tmplContent)
+	t, err := parseHTMLTemplate(report, "fsdStructure", tmplContent)
 	if err != nil {
-		return fmt.Errorf("ошибка при парсинге HTML шаблона: %v", err)
+		return err
 	}
 
-	if err := t.Execute(outputFile, templateData); err != nil {
-		return fmt.Errorf("ошибка при генерации HTML: %v", err)
+	if err := t.Execute(w, templateData); err != nil {
+		return i18n.Errorf("ошибка при генерации HTML: %v", err)
 	}
 
 	return nil
 }
 
 const defaultHTMLTemplate = `<!DOCTYPE html>
-<html lang="ru">
+<html lang="{{locale}}">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>FSD Structure Analyzer</title>
-    <style>
-        body {
-            font-family: Arial, sans-serif;
-            line-height: 1.6;
-            margin: 0;
-            padding: 20px;
-            color: #333;
-        }
-        h1, h2 {
-            color: #2c3e50;
-            margin-bottom: 20px;
-        }
-        .container {
-            max-width: 1200px;
-            margin: 0 auto;
-        }
-        .layer {
-            margin-bottom: 30px;
-            border: 1px solid #ddd;
-            border-radius: 4px;
-            overflow: hidden;
-        }
-        .layer-header {
-            background-color: #f5f5f5;
-            padding: 10px 15px;
-            font-weight: bold;
-            border-bottom: 1px solid #ddd;
-        }
-        .slice {
-            margin: 15px;
-            border: 1px solid #e0e0e0;
-            border-radius: 4px;
-            overflow: hidden;
-        }
-        .slice-header {
-            background-color: #f9f9f9;
-            padding: 8px 12px;
-            font-weight: bold;
-            border-bottom: 1px solid #e0e0e0;
-        }
-        .segment {
-            margin: 10px;
-            border: 1px solid #eee;
-            border-radius: 4px;
-        }
-        .segment-header {
-            background-color: #fafafa;
-            padding: 6px 10px;
-            font-weight: bold;
-            border-bottom: 1px solid #eee;
-        }
-        .files {
-            padding: 5px 10px;
-        }
-        .file {
-            padding: 3px 0;
-            font-size: 14px;
-        }
-        .empty-message {
-            padding: 10px;
-            color: #999;
-            font-style: italic;
-        }
-        .dependencies-section {
-            margin-top: 40px;
-        }
-        .dependency-list {
-            margin-top: 20px;
-        }
-        .dependency-item {
-            padding: 8px;
-            margin-bottom: 5px;
-            border-radius: 4px;
-        }
-        .dependency-normal {
-            background-color: #d4edda;
-            border: 1px solid #c3e6cb;
-            color: #155724;
-        }
-        .dependency-same {
-            background-color: #fff3cd;
-            border: 1px solid #ffeeba;
-            color: #856404;
-        }
-        .dependency-cyclical {
-            background-color: #f8d7da;
-            border: 1px solid #f5c6cb;
-            color: #721c24;
-        }
-        .dependency-test {
-            background-color: #e2e3e5;
-            border: 1px solid #d6d8db;
-            color: #383d41;
-        }
-        .dependency-allowed-cyclical {
-            background-color: #d4edda;
-            border: 1px solid #c3e6cb;
-            color: #155724;
-            opacity: 0.7;
-        }
-        .dependency-graph {
-            position: relative;
-            height: 600px;
-            border: 1px solid #ddd;
-            border-radius: 4px;
-            margin-top: 20px;
-            overflow: hidden;
-        }
-        .dependency-graph svg {
-            width: 100%;
-            height: auto;
-            min-height: 500px;
-            border: 1px solid #ddd;
-        }
-        .node {
-            cursor: pointer;
-        }
-        .link {
-            stroke-width: 2px;
-        }
-        .link-normal {
-            stroke: #28a745;
-        }
-        .link-same {
-            stroke: #ffc107;
-        }
-        .link-cyclical {
-            stroke: #dc3545;
-        }
-        .link-test {
-            stroke: #6c757d;
-        }
-        .controls {
-            position: absolute;
-            top: 10px;
-            right: 10px;
-            background: rgba(255, 255, 255, 0.8);
-            padding: 10px;
-            border-radius: 4px;
-            border: 1px solid #ddd;
-            z-index: 10;
-        }
-        .controls button {
-            margin: 0 5px;
-            padding: 5px 10px;
-            background: #4a69bd;
-            color: white;
-            border: none;
-            border-radius: 4px;
-            cursor: pointer;
-        }
-        .controls button:hover {
-            background: #3a5795;
-        }
-        .node-label {
-            font-size: 12px;
-            pointer-events: none;
-        }
-        .node circle {
-            stroke: #fff;
-            stroke-width: 2px;
-        }
-        .tooltip {
-            position: absolute;
-            background: white;
-            border: 1px solid #ddd;
-            border-radius: 4px;
-            padding: 10px;
-            pointer-events: none;
-            opacity: 0;
-            transition: opacity 0.3s;
-        }
-    </style>
-    <script src="https://d3js.org/d3.v7.min.js"></script>
+    {{.Assets.Styles}}
 </head>
 <body>
     <div class="container">
         <h1>Feature-Sliced Design Structure Analyzer</h1>
         
-        {{if .Layers}}
-            {{range .Layers}}
-                <div class="layer">
-                    <div class="layer-header">Слой: {{.Name}}</div>
+        {{template "structure" .}}
+
+        {{template "history" .}}
+
+        {{template "issues" .}}
+
+        {{template "dependencies" .}}
+    </div>
+    {{template "source-preview" .}}
+    {{template "scripts" .}}
+</body>
+</html>`
+
+// htmlPartialsTemplate — блоки стандартного HTML-отчета. Они доступны и
+// пользовательским шаблонам ({{template "dependencies" .}}), а файлы из
+// html.partials могут их переопределить.
+const htmlPartialsTemplate = `{{define "treemap"}}
+<div class="treemap-section">
+    <h2>{{t "Объем кода"}}</h2>
+    <div class="treemap-controls">
+        <label>{{t "Вид:"}}
+            <select id="treemap-view">
+                <option value="treemap">treemap</option>
+                <option value="sunburst">sunburst</option>
+            </select>
+        </label>
+        <label>{{t "Размер:"}}
+            <select id="treemap-size">
+                <option value="lines">{{t "строки"}}</option>
+                <option value="bytes">{{t "байты"}}</option>
+                <option value="files">{{t "файлы"}}</option>
+            </select>
+        </label>
+        <label>{{t "Цвет:"}}
+            <select id="treemap-color">
+                <option value="layer">{{t "слой"}}</option>
+                <option value="violations">{{t "нарушения"}}</option>
+                <option value="instability">{{t "нестабильность"}}</option>
+            </select>
+        </label>
+        <span class="treemap-path" id="treemap-path"></span>
+    </div>
+    <div class="structure-treemap" id="structure-treemap"></div>
+</div>
+{{end}}
+
+{{define "history"}}
+{{if .History}}
+<div class="history-section">
+    <h2>{{t "Динамика по коммитам"}}</h2>
+    <div class="history-controls">
+        <label>{{t "Метрика:"}}
+            <select id="history-metric">
+                <option value="violations">{{t "нарушения"}}</option>
+                <option value="dependencies">{{t "зависимости"}}</option>
+                <option value="slices">{{t "слайсы"}}</option>
+                <option value="files">{{t "файлы"}}</option>
+                <option value="lines">{{t "строки"}}</option>
+            </select>
+        </label>
+        <span class="history-range">{{t "коммитов: %d" (len .History)}}</span>
+    </div>
+    <div class="history-chart" id="history-chart"></div>
+</div>
+{{end}}
+{{end}}
+
+{{define "structure"}}
+{{if .Layers}}
+    {{template "treemap" .}}
+
+    {{range .Layers}}
+        <div class="layer">
+            <div class="layer-header">{{t "Слой: %s" .Name}}</div>
+            
+            {{range .Slices}}
+                <div class="slice">
+                    {{if .Name}}
+                        <div class="slice-header">{{t "Слайс: %s" .Name}}</div>
+                    {{end}}
                     
-                    {{range .Slices}}
-                        <div class="slice">
-                            {{if .Name}}
-                                <div class="slice-header">Слайс: {{.Name}}</div>
-                            {{end}}
-                            
-                            {{range .Segments}}
-                                <div class="segment">
-                                    <div class="segment-header">Сегмент: {{.Name}}</div>
-                                    {{if .Files}}
-                                        <div class="files">
-                                            {{range .Files}}
-                                                <div class="file">{{.}}</div>
-                                            {{end}}
-                                        </div>
-                                    {{else}}
-                                        <div class="files">Нет файлов</div>
+                    {{range .Segments}}
+                        {{$segment := .}}
+                        <div class="segment">
+                            <div class="segment-header">{{t "Сегмент: %s" .Name}}</div>
+                            {{if .Files}}
+                                <div class="files">
+                                    {{range .Files}}
+                                        <div class="file">{{.}}{{with index $segment.Stats .}}{{if .Bytes}} <span class="file-size">{{t "%d строк, %d байт" .Lines .Bytes}}</span>{{end}}{{end}}</div>
                                     {{end}}
                                 </div>
                             {{else}}
-                                <div class="empty-message">Нет сегментов</div>
+                                <div class="files">{{t "Нет файлов"}}</div>
                             {{end}}
                         </div>
                     {{else}}
-                        <div class="empty-message">Нет слайсов</div>
+                        <div class="empty-message">{{t "Нет сегментов"}}</div>
+                    {{end}}
+                    {{if .Exports}}
+                        <div class="segment">
+                            <div class="segment-header">{{t "Публичный API"}}</div>
+                            <div class="files">
+                                {{range .Exports}}
+                                    <div class="file">{{.}}</div>
+                                {{end}}
+                            </div>
+                        </div>
                     {{end}}
                 </div>
+            {{else}}
+                <div class="empty-message">{{t "Нет слайсов"}}</div>
             {{end}}
-        {{else}}
-            <div class="empty-message">FSD структура не обнаружена</div>
+        </div>
+    {{end}}
+{{else}}
+    <div class="empty-message">{{t "FSD структура не обнаружена"}}</div>
+{{end}}
+{{end}}
+
+{{define "issues"}}
+{{if .APIIssues}}
+    <div class="api-issues-section">
+        <h2>{{t "Проблемы публичного API"}}</h2>
+        {{range .APIIssues}}
+            <div class="api-issue api-issue-{{.Kind}}" data-source-file="{{.File}}" data-source-line="{{.Line}}">
+                {{if eq .Kind "unused-export"}}
+                    {{.Layer}}/{{.Slice}}: {{t "экспорт"}} <code>{{.Name}}</code> {{t "не используется другими слайсами"}} ({{.File}}:{{.Line}})
+                {{else}}
+                    {{.Layer}}/{{.Slice}}: <code>{{.Name}}</code> {{t "импортируется в обход публичного API"}} ({{.File}}:{{.Line}})
+                {{end}}
+            </div>
         {{end}}
+    </div>
+{{end}}
 
-        {{if .HasDependencies}}
-            <div class="dependencies-section">
-                <h2>Зависимости между слоями и слайсами</h2>
-                
-                {{if .AllowedCyclicalDependencies}}
-                    <div class="allowed-cyclical-info">
-                        <h3>Слайсы с разрешенными циклическими зависимостями:</h3>
-                        <ul>
-                            {{range .AllowedCyclicalDependencies}}
-                                <li>{{.}}</li>
-                            {{end}}
-                        </ul>
-                    </div>
+{{if .BarrelIssues}}
+    <div class="api-issues-section">
+        <h2>{{t "Реэкспорты (barrel-файлы)"}}</h2>
+        {{range .BarrelIssues}}
+            <div class="api-issue api-issue-{{.Kind}}" data-source-file="{{.File}}" data-source-line="{{.Line}}">
+                {{if eq .Kind "deep-chain"}}
+                    {{.Layer}}/{{.Slice}}: {{t "глубокая цепочка export *"}} ({{.File}}:{{.Line}}):
+                    {{range $i, $file := .Chain}}{{if $i}} → {{end}}{{$file}}{{end}}
+                {{else}}
+                    {{.Layer}}/{{.Slice}}: {{t "реэкспортирует слайс %s/%s" .TargetLayer .TargetSlice}} ({{.File}}:{{.Line}})
                 {{end}}
-                
-                <div class="dependency-graph" id="dependency-graph">
-                    <div class="controls">
-                        <button id="zoom-in">+</button>
-                        <button id="zoom-out">-</button>
-                    </div>
-                </div>
-                
-                <div class="dependency-list">
-                    <h3>Список зависимостей</h3>
-                    {{range .Dependencies}}
-                        {{$dep := .}}
-                        {{$isAllowedCyclical := false}}
-                        {{$fromPath := (print $dep.FromLayer "/" $dep.FromSlice)}}
-                        {{$toPath := (print $dep.ToLayer "/" $dep.ToSlice)}}
-                        
-                        {{if eq $dep.Type "cyclical"}}
-                            {{range $index, $element := $.AllowedCyclicalDependencies}}
-                                {{if or (eq $element $fromPath) (eq $element $toPath) (eq $element $dep.FromLayer) (eq $element $dep.ToLayer)}}
-                                    {{$isAllowedCyclical = true}}
-                                {{end}}
-                            {{end}}
-                        {{end}}
-                        
-                        <div class="dependency-item {{if $isAllowedCyclical}}dependency-allowed-cyclical{{else}}dependency-{{$dep.Type}}{{end}}">
-                            {{$dep.FromLayer}}/{{$dep.FromSlice}} → {{$dep.ToLayer}}/{{$dep.ToSlice}}
-                            {{if $isAllowedCyclical}}
-                                (разрешенная циклическая зависимость)
-                            {{else if eq $dep.Type "normal"}}
-                                (нормальная зависимость)
-                            {{else if eq $dep.Type "same"}}
-                                (зависимость на том же слое)
-                            {{else if eq $dep.Type "cyclical"}}
-                                (циклическая зависимость)
-                            {{else if eq $dep.Type "test"}}
-                                (тестовая зависимость)
-                            {{end}}
-                        </div>
+            </div>
+        {{end}}
+    </div>
+{{end}}
+
+{{if .SegmentViolations}}
+    <div class="api-issues-section">
+        <h2>{{t "Нарушения правил сегментов"}}</h2>
+        {{range .SegmentViolations}}
+            <div class="api-issue api-issue-segment-violation" data-source-file="{{.File}}" data-source-line="{{.Line}}">
+                {{.FromLayer}}/{{.FromSlice}}/{{.FromSegment}} → {{.ToLayer}}/{{.ToSlice}}/{{.ToSegment}}:
+                {{t "сегмент %s не должен импортировать %s" .Rule.From .Rule.To}}
+                {{if eq .Rule.Scope "slice"}}{{t "внутри слайса"}}{{else if eq .Rule.Scope "cross"}}{{t "из других слайсов"}}{{end}}
+                ({{.File}}:{{.Line}})
+            </div>
+        {{end}}
+    </div>
+{{end}}
+
+{{if .SegmentCycles}}
+    <div class="api-issues-section">
+        <h2>{{t "Циклы между сегментами внутри слайса"}}</h2>
+        {{range .SegmentCycles}}
+            <div class="api-issue api-issue-segment-cycle">
+                {{.Layer}}/{{.Slice}}: {{range $i, $segment := .Segments}}{{if $i}} ⇄ {{end}}{{$segment}}{{end}}
+                <ul>
+                    {{range .Imports}}
+                        <li data-source-file="{{.FromFile}}" data-source-line="{{.Line}}">{{.FromFile}}:{{.Line}} ({{.FromSegment}} → {{.ToSegment}})</li>
                     {{end}}
-                </div>
+                </ul>
             </div>
+        {{end}}
+    </div>
+{{end}}
+{{end}}
 
-            <script>
-                document.addEventListener('DOMContentLoaded', function() {
-                    const allowedCyclicalPaths = [
-                        {{range .AllowedCyclicalDependencies}}
-                            "{{.}}",
-                        {{end}}
-                    ];
-                    
-                    const dependencies = [
-                        {{range .Dependencies}}
-                            {
-                                source: "{{.FromLayer}}/{{.FromSlice}}",
-                                target: "{{.ToLayer}}/{{.ToSlice}}",
-                                type: "{{.Type}}",
-                                fromLayer: "{{.FromLayer}}",
-                                fromSlice: "{{.FromSlice}}",
-                                toLayer: "{{.ToLayer}}",
-                                toSlice: "{{.ToSlice}}"
-                            },
-                        {{end}}
-                    ];
-                    
-                    dependencies.forEach(d => {
-                        const fromPath = d.fromLayer + "/" + d.fromSlice;
-                        const toPath = d.toLayer + "/" + d.toSlice;
-                        
-                        if (d.type === "cyclical" && 
-                            (allowedCyclicalPaths.includes(fromPath) || 
-                             allowedCyclicalPaths.includes(toPath) ||
-                             allowedCyclicalPaths.includes(d.fromLayer) ||
-                             allowedCyclicalPaths.includes(d.toLayer))) {
-                            d.type = "normal";
-                            d.isAllowedCyclical = true;
-                        }
-                    });
-                    
-                    const nodes = new Set();
-                    dependencies.forEach(d => {
-                        nodes.add(d.source);
-                        nodes.add(d.target);
-                    });
-                    
-                    const nodesArray = Array.from(nodes).map(id => {
-                        const parts = id.split('/');
-                        const layerName = parts[0];
-                        return { 
-                            id,
-                            layerName,
-                            isAllowedCyclical: allowedCyclicalPaths.includes(id) || allowedCyclicalPaths.includes(layerName)
-                        };
-                    });
-                    
-                    const links = dependencies.map(d => ({
-                        source: d.source,
-                        target: d.target,
-                        type: d.type,
-                        isAllowedCyclical: d.isAllowedCyclical
-                    }));
-                    
-                    const width = document.getElementById('dependency-graph').clientWidth;
-                    const height = 600;
-                    
-                    const svg = d3.select('#dependency-graph')
-                        .append('svg')
-                        .attr('width', width)
-                        .attr('height', height)
-                        .attr('viewBox', [0, 0, width, height])
-                        .call(d3.zoom()
-                            .extent([[0, 0], [width, height]])
-                            .scaleExtent([0.1, 4])
-                            .on("zoom", zoomed));
-                    
-                    const tooltip = d3.select('#dependency-graph')
-                        .append('div')
-                        .attr('class', 'tooltip');
-                        
-                    const g = svg.append('g');
-                    
-                    function zoomed(event) {
-                        g.attr('transform', event.transform);
-                    }
-                    
-                    const linksGroup = g.append('g').attr('class', 'links');
-                    const nodesGroup = g.append('g').attr('class', 'nodes');
-                    
-                    const simulation = d3.forceSimulation(nodesArray)
-                        .force('link', d3.forceLink(links)
-                            .id(d => d.id)
-                            .distance(100)
-                            .strength(0.5))
-                        .force('charge', d3.forceManyBody()
-                            .strength(-300)
-                            .distanceMax(500))
-                        .force('center', d3.forceCenter(width / 2, height / 2))
-                        .force('collide', d3.forceCollide().radius(50))
-                        .force('x', d3.forceX(width / 2).strength(0.05))
-                        .force('y', d3.forceY(height / 2).strength(0.05));
-                    
-                    svg.append('defs').selectAll('marker')
-                        .data(['normal', 'same', 'cyclical', 'test', 'allowed-cyclical'])
-                        .enter()
-                        .append('marker')
-                        .attr('id', d => 'arrow-' + d)
-                        .attr('viewBox', '0 -5 10 10')
-                        .attr('refX', 25)
-                        .attr('refY', 0)
-                        .attr('markerWidth', 6)
-                        .attr('markerHeight', 6)
-                        .attr('orient', 'auto')
-                        .append('path')
-                        .attr('d', 'M0,-5L10,0L0,5')
-                        .attr('fill', d => {
-                            switch(d) {
-                                case 'normal': return '#28a745';
-                                case 'same': return '#ffc107';
-                                case 'cyclical': return '#dc3545';
-                                case 'test': return '#6c757d';
-                                case 'allowed-cyclical': return '#28a745';
-                                default: return '#28a745';
-                            }
-                        });
-                    
-                    const link = linksGroup.selectAll('line')
-                        .data(links)
-                        .enter()
-                        .append('line')
-                        .attr('class', d => 'link link-' + d.type)
-                        .attr('stroke', d => {
-                            if (d.isAllowedCyclical) return '#28a745';
-                            switch(d.type) {
-                                case 'normal': return '#28a745';
-                                case 'same': return '#ffc107';
-                                case 'cyclical': return '#dc3545';
-                                case 'test': return '#6c757d';
-                                default: return '#28a745';
-                            }
-                        })
-                        .attr('stroke-opacity', d => d.isAllowedCyclical ? 0.7 : 1)
-                        .attr('marker-end', d => d.isAllowedCyclical ? 
-                            'url(#arrow-allowed-cyclical)' : 'url(#arrow-' + d.type + ')');
-                    
-                    const node = nodesGroup.selectAll('.node')
-                        .data(nodesArray)
-                        .enter()
-                        .append('g')
-                        .attr('class', 'node')
-                        .call(d3.drag()
-                            .on('start', dragstarted)
-                            .on('drag', dragged)
-                            .on('end', dragended))
-                        .on('mouseover', function(event, d) {
-                            tooltip.style('opacity', 1)
-                                .html('<strong>' + d.id + '</strong>' + 
-                                    (d.isAllowedCyclical ? ' (разрешены циклические зависимости)' : ''))
-                                .style('left', (event.pageX - document.getElementById('dependency-graph').offsetLeft + 10) + 'px')
-                                .style('top', (event.pageY - document.getElementById('dependency-graph').offsetTop - 30) + 'px');
-                        })
-                        .on('mouseout', function() {
-                            tooltip.style('opacity', 0);
-                        });
-                    
-                    node.append('circle')
-                        .attr('r', 15)
-                        .attr('fill', d => {
-                            const color = getNodeColor(d.id);
-                            return d.isAllowedCyclical ? d3.color(color).brighter(0.3) : color;
-                        })
-                        .attr('opacity', d => d.isAllowedCyclical ? 0.8 : 1);
-                    
-                    function getNodeColor(id) {
-                        const layer = id.split('/')[0];
-                        switch(layer) {
-                            case 'app': return '#3498db';
-                            case 'processes': return '#9b59b6';
-                            case 'pages': return '#2ecc71';
-                            case 'widgets': return '#f1c40f';
-                            case 'features': return '#e67e22';
-                            case 'entities': return '#e74c3c';
-                            case 'shared': return '#95a5a6';
-                            default: return '#34495e';
-                        }
-                    }
-                    
-                    node.append('text')
-                        .attr('class', 'node-label')
-                        .attr('dx', 12)
-                        .attr('dy', '.35em')
-                        .text(d => d.id);
-                    
-                    simulation.on('tick', () => {
-                        link
-                            .attr('x1', d => d.source.x)
-                            .attr('y1', d => d.source.y)
-                            .attr('x2', d => d.target.x)
-                            .attr('y2', d => d.target.y);
-                        
-                        node.attr('transform', d => 'translate(' + d.x + ',' + d.y + ')');
-                    });
-                    
-                    function dragstarted(event, d) {
-                        if (!event.active) simulation.alphaTarget(0.3).restart();
-                        d.fx = d.x;
-                        d.fy = d.y;
-                    }
-                    
-                    function dragged(event, d) {
-                        d.fx = event.x;
-                        d.fy = event.y;
-                    }
-                    
-                    function dragended(event, d) {
-                        if (!event.active) simulation.alphaTarget(0);
-                        d.fx = null;
-                        d.fy = null;
-                    }
-                    
-                    document.getElementById('zoom-in').addEventListener('click', function() {
-                        svg.transition().call(
-                            d3.zoom().on('zoom', zoomed).transform,
-                            d3.zoomIdentity.scale(d3.zoomTransform(svg.node()).k * 1.3)
-                        );
-                    });
-                    
-                    document.getElementById('zoom-out').addEventListener('click', function() {
-                        svg.transition().call(
-                            d3.zoom().on('zoom', zoomed).transform,
-                            d3.zoomIdentity.scale(d3.zoomTransform(svg.node()).k / 1.3)
-                        );
-                    });
-                    
-                    simulation.alpha(1).restart();
-                });
-            </script>
-        {{else}}
-            <div class="empty-message">Зависимости не обнаружены</div>
+{{define "dependencies"}}
+{{if .HasDependencies}}
+    <div class="dependencies-section">
+        <h2>{{t "Зависимости между слоями и слайсами"}}</h2>
+        
+        {{if .AllowedCyclicalDependencies}}
+            <div class="allowed-cyclical-info">
+                <h3>{{t "Слайсы с разрешенными циклическими зависимостями:"}}</h3>
+                <ul>
+                    {{range .AllowedCyclicalDependencies}}
+                        <li>{{.}}</li>
+                    {{end}}
+                </ul>
+            </div>
         {{end}}
+        
+        <div class="report-filters" id="report-filters">
+            <div class="filter-row">
+                <input id="filter-search" type="search" placeholder="{{t "поиск по слайсу или файлу"}}">
+                <label>{{t "фокус:"}} <input id="filter-focus" list="why-nodes" placeholder="{{t "например pages/home"}}"></label>
+                <label>{{t "соседей:"}} <input id="filter-hops" type="number" min="1" value="1"></label>
+                <button id="filter-reset" type="button">{{t "Сбросить"}}</button>
+            </div>
+            <div class="filter-row">
+                {{t "Типы:"}}
+                <label><input type="checkbox" data-filter-type="normal" checked> {{t "нормальные"}}</label>
+                <label><input type="checkbox" data-filter-type="same" checked> {{t "на том же слое"}}</label>
+                <label><input type="checkbox" data-filter-type="cyclical" checked> {{t "циклические"}}</label>
+                <label><input type="checkbox" data-filter-type="test" checked> {{t "тестовые"}}</label>
+            </div>
+            <div class="filter-row" id="filter-layers">{{t "Слои:"}}</div>
+        </div>
+
+        <div class="dependency-graph" id="dependency-graph">
+            <div class="controls">
+                <button id="zoom-in">+</button>
+                <button id="zoom-out">-</button>
+                <button id="layout-layered" class="layout-toggle">{{t "Слои"}}</button>
+                <button id="layout-force" class="layout-toggle">{{t "Граф сил"}}</button>
+            </div>
+        </div>
+        
+        <div class="dependency-list">
+            <h3>{{t "Список зависимостей"}} <span class="dependency-count" id="dependency-count"></span></h3>
+            {{range .Dependencies}}
+                {{$dep := .}}
+                {{$isAllowedCyclical := allowedCyclical $dep}}
+                <div class="dependency-item {{if $isAllowedCyclical}}dependency-allowed-cyclical{{else}}dependency-{{$dep.Type}}{{end}}"
+                     data-from-layer="{{$dep.FromLayer}}" data-from-slice="{{$dep.FromSlice}}"
+                     data-to-layer="{{$dep.ToLayer}}" data-to-slice="{{$dep.ToSlice}}"
+                     data-type="{{if $isAllowedCyclical}}normal{{else}}{{$dep.Type}}{{end}}"
+                     data-source-file="{{$dep.FromFile}}" data-source-line="{{$dep.Line}}">
+                    {{$dep.FromLayer}}/{{$dep.FromSlice}} → {{$dep.ToLayer}}/{{$dep.ToSlice}}
+                    {{if $isAllowedCyclical}}
+                        ({{t "разрешенная циклическая зависимость"}})
+                    {{else if eq $dep.Type "normal"}}
+                        ({{t "нормальная зависимость"}})
+                    {{else if eq $dep.Type "same"}}
+                        ({{t "зависимость на том же слое"}})
+                    {{else if eq $dep.Type "cyclical"}}
+                        ({{t "циклическая зависимость"}})
+                    {{else if eq $dep.Type "test"}}
+                        ({{t "тестовая зависимость"}})
+                    {{end}}
+                </div>
+            {{end}}
+        </div>
+
+        <div class="dsm" id="dsm">
+            <h3>{{t "Матрица зависимостей (DSM)"}}</h3>
+            {{template "dsm" .DSM}}
+        </div>
+
+        <div class="why-panel">
+            <h3>{{t "Почему один слайс зависит от другого?"}}</h3>
+            <div class="why-controls">
+                <input id="why-from" list="why-nodes" placeholder="{{t "откуда, например pages/home"}}">
+                <input id="why-to" list="why-nodes" placeholder="{{t "куда, например entities/user"}}">
+                <label>{{t "кратчайших путей:"}} <input id="why-limit" type="number" min="0" value="10"></label>
+                <button id="why-run">{{t "Найти пути"}}</button>
+                <datalist id="why-nodes"></datalist>
+            </div>
+            <div id="why-result"></div>
+        </div>
     </div>
-</body>
-</html>` 
\ No newline at end of file
+{{else}}
+    <div class="empty-message">{{t "Зависимости не обнаружены"}}</div>
+{{end}}
+{{end}}
+
+{{define "source-preview"}}
+<div class="source-preview" id="source-preview" hidden>
+    <div class="source-preview-header">
+        <span id="source-preview-title"></span>
+        <button id="source-preview-close" type="button">×</button>
+    </div>
+    <pre class="source-preview-code" id="source-preview-code"></pre>
+</div>
+{{end}}
+
+{{define "scripts"}}
+<script>window.FSD_REPORT = {{.ReportData}};</script>
+{{.Assets.Scripts}}
+{{end}}`
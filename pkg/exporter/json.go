@@ -3,51 +3,641 @@ package exporter
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"os"
-	"path/filepath"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"fsd-crawler/pkg/config"
 	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
 	"fsd-crawler/pkg/model"
 )
 
+type jsonExporter struct{}
+
+func init() {
+	Register(jsonExporter{})
+}
+
+func (jsonExporter) Name() string {
+	return "json"
+}
+
+func (jsonExporter) FileName() string {
+	return "fsd_structure.json"
+}
+
+func (jsonExporter) Export(w io.Writer, report *Report) error {
+	return writeJSONReport(w, report)
+}
+
 func ExportJSON(structure *model.ProjectStructure, cfg *config.Config) error {
-	outputDir := "./dist"
-	if cfg != nil && cfg.OutputDir != "" {
-		outputDir = cfg.OutputDir
+	_, err := Run("json", structure, cfg)
+	return err
+}
+
+func writeJSONReport(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(BuildJSONReport(report, reportTime())); err != nil {
+		return i18n.Errorf("ошибка при кодировании в JSON: %v", err)
+	}
+
+	return nil
+}
+
+// reportTime возвращает время формирования отчета. Переменная окружения
+// SOURCE_DATE_EPOCH фиксирует его для воспроизводимых отчетов.
+func reportTime() time.Time {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+	}
+	return time.Now().UTC()
+}
+
+func nodeID(kind, nodePath string) string {
+	return kind + ":" + nodePath
+}
+
+func segmentPath(slicePath, segment string) string {
+	if segment == "root" {
+		return slicePath
 	}
+	return path.Join(slicePath, segment)
+}
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("не удалось создать директорию для вывода: %v", err)
+func emptyIfNil(items []string) []string {
+	if items == nil {
+		return []string{}
 	}
+	return items
+}
 
-	outputPath := filepath.Join(outputDir, "fsd_structure.json")
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("не удалось создать JSON файл: %v", err)
+func sortedCopy(items []string) []string {
+	sorted := append([]string{}, items...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// BuildJSONReport преобразует результаты анализа в документ схемы
+// ReportSchemaVersion.
+func BuildJSONReport(report *Report, generatedAt time.Time) *JSONReport {
+	doc := &JSONReport{
+		Schema:        reportSchemaURI,
+		SchemaVersion: ReportSchemaVersion,
+		Metadata: ReportMetadata{
+			Tool:        toolName,
+			GeneratedAt: generatedAt.Format(time.RFC3339),
+			Config:      buildReportConfig(report.Config),
+		},
+		Nodes:         []ReportNode{},
+		Edges:         []ReportEdge{},
+		InternalEdges: []ReportEdge{},
+		Violations:    []ReportViolation{},
+		Issues: ReportIssues{
+			PublicAPI:         []ReportAPIIssue{},
+			Barrels:           []ReportBarrelIssue{},
+			SegmentViolations: []ReportSegmentViolation{},
+			SegmentCycles:     []ReportSegmentCycle{},
+		},
 	}
-	defer outputFile.Close()
 
-	exportData := struct {
-		Layers       []*model.FSDLayer       `json:"layers"`
-		Dependencies []dependencies.Dependency `json:"dependencies"`
-	}{
-		Layers:       structure.Layers,
-		Dependencies: []dependencies.Dependency{},
+	doc.Nodes = buildReportNodes(report.Structure)
+
+	deps := sortedDependencies(report.Dependencies)
+	edgeIDs := make(map[string][]string)
+	for _, edge := range buildReportEdges(deps) {
+		key := dependencyKey(edge.FromFile, edge.Line, edge.ImportPath, edge.ToFile)
+		edgeIDs[key] = append(edgeIDs[key], edge.ID)
+		doc.Edges = append(doc.Edges, edge)
+	}
+	// Импорты внутри слайса получают идентификаторы того же вида: на них
+	// ссылаются циклы сегментов
+	for _, edge := range buildReportEdges(sortedDependencies(report.Structure.InternalImports)) {
+		key := dependencyKey(edge.FromFile, edge.Line, edge.ImportPath, edge.ToFile)
+		edgeIDs[key] = append(edgeIDs[key], edge.ID)
+		doc.InternalEdges = append(doc.InternalEdges, edge)
+	}
+	edgeOf := func(dep dependencies.Dependency) string {
+		if ids := edgeIDs[dependencyKey(dep.FromFile, dep.Line, dep.ImportPath, dep.ToFile)]; len(ids) > 0 {
+			return ids[0]
+		}
+		return ""
 	}
 
-	for _, dep := range structure.Dependencies {
-		if d, ok := dep.(dependencies.Dependency); ok {
-			exportData.Dependencies = append(exportData.Dependencies, d)
+	// Нарушения собираются по отсортированным зависимостям, чтобы отпечатки
+	// не зависели от порядка обхода файлов.
+	sorted := *report.Structure
+	sorted.Dependencies = deps
+	violations := CollectViolations(&sorted)
+	for i, fingerprint := range violationFingerprints(violations) {
+		violation := violations[i]
+		doc.Violations = append(doc.Violations, ReportViolation{
+			ID:       fingerprint,
+			Rule:     violation.RuleID,
+			Severity: ruleLevel(violation.RuleID),
+			Message:  violation.Message,
+			File:     violation.File,
+			Line:     violation.Line,
+			Edge:     edgeOf(violation.Dependency),
+		})
+	}
+	sort.SliceStable(doc.Violations, func(i, j int) bool {
+		a, b := doc.Violations[i], doc.Violations[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
 		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.ID < b.ID
+	})
+
+	for _, issue := range report.APIIssues {
+		doc.Issues.PublicAPI = append(doc.Issues.PublicAPI, ReportAPIIssue{
+			Kind:  string(issue.Kind),
+			Layer: issue.Layer,
+			Slice: issue.Slice,
+			Name:  issue.Name,
+			File:  issue.File,
+			Line:  issue.Line,
+		})
 	}
 
-	encoder := json.NewEncoder(outputFile)
-	encoder.SetIndent("", "  ")
+	for _, issue := range report.BarrelIssues {
+		doc.Issues.Barrels = append(doc.Issues.Barrels, ReportBarrelIssue{
+			Kind:        string(issue.Kind),
+			Layer:       issue.Layer,
+			Slice:       issue.Slice,
+			File:        issue.File,
+			Line:        issue.Line,
+			TargetLayer: issue.TargetLayer,
+			TargetSlice: issue.TargetSlice,
+			Chain:       issue.Chain,
+		})
+	}
 
-	if err := encoder.Encode(exportData); err != nil {
-		return fmt.Errorf("ошибка при кодировании в JSON: %v", err)
+	for _, violation := range report.SegmentViolations {
+		doc.Issues.SegmentViolations = append(doc.Issues.SegmentViolations, ReportSegmentViolation{
+			Rule:        ReportSegmentRule{From: violation.Rule.From, To: violation.Rule.To, Scope: violation.Rule.Scope},
+			FromLayer:   violation.FromLayer,
+			FromSlice:   violation.FromSlice,
+			FromSegment: violation.FromSegment,
+			ToLayer:     violation.ToLayer,
+			ToSlice:     violation.ToSlice,
+			ToSegment:   violation.ToSegment,
+			File:        violation.File,
+			Line:        violation.Line,
+			ImportPath:  violation.ImportPath,
+		})
 	}
 
-	return nil
-} 
\ No newline at end of file
+	for _, cycle := range report.SegmentCycles {
+		reportCycle := ReportSegmentCycle{
+			Layer:    cycle.Layer,
+			Slice:    cycle.Slice,
+			Segments: emptyIfNil(cycle.Segments),
+			Edges:    []string{},
+		}
+		for _, dep := range cycle.Imports {
+			if id := edgeOf(dep); id != "" {
+				reportCycle.Edges = append(reportCycle.Edges, id)
+			}
+		}
+		sort.Strings(reportCycle.Edges)
+		doc.Issues.SegmentCycles = append(doc.Issues.SegmentCycles, reportCycle)
+	}
+
+	doc.Metrics = buildReportMetrics(doc)
+
+	return doc
+}
+
+func buildReportConfig(cfg *config.Config) ReportConfig {
+	reportConfig := ReportConfig{
+		Layers:                      append([]string{}, model.KnownLayers...),
+		ExcludeDirs:                 []string{},
+		Aliases:                     map[string]string{},
+		AllowedCyclicalDependencies: []string{},
+		SegmentRules:                []ReportSegmentRule{},
+	}
+	if cfg == nil {
+		return reportConfig
+	}
+
+	reportConfig.SrcDir = cfg.SrcDir
+	reportConfig.ExcludeDirs = sortedCopy(cfg.ExcludeDirs)
+	reportConfig.AllowedCyclicalDependencies = sortedCopy(cfg.AllowedCyclicalDependencies)
+	reportConfig.MaxReexportDepth = cfg.MaxReexportDepth
+	for alias, target := range cfg.Aliases {
+		reportConfig.Aliases[alias] = target
+	}
+	for _, rule := range cfg.SegmentRules {
+		reportConfig.SegmentRules = append(reportConfig.SegmentRules, ReportSegmentRule{From: rule.From, To: rule.To, Scope: rule.Scope})
+	}
+
+	return reportConfig
+}
+
+// buildReportNodes обходит иерархию в порядке слоев; слайсы, сегменты и
+// файлы внутри родителя отсортированы по пути.
+func buildReportNodes(structure *model.ProjectStructure) []ReportNode {
+	nodes := []ReportNode{}
+
+	layers := append([]*model.FSDLayer{}, structure.Layers...)
+	sort.SliceStable(layers, func(i, j int) bool {
+		return layerOrder(layers[i].Name) < layerOrder(layers[j].Name)
+	})
+
+	for _, layer := range layers {
+		layerNode := nodeID(NodeLayer, layer.Name)
+		nodes = append(nodes, ReportNode{ID: layerNode, Kind: NodeLayer, Name: layer.Name, Path: layer.Name})
+
+		slices := append([]*model.FSDSlice{}, layer.Slices...)
+		sort.SliceStable(slices, func(i, j int) bool {
+			return sliceID(layer.Name, slices[i].Name) < sliceID(layer.Name, slices[j].Name)
+		})
+
+		for _, slice := range slices {
+			slicePath := sliceID(layer.Name, slice.Name)
+			sliceNode := nodeID(NodeSlice, slicePath)
+			nodes = append(nodes, ReportNode{
+				ID:      sliceNode,
+				Kind:    NodeSlice,
+				Name:    slice.Name,
+				Path:    slicePath,
+				Parent:  layerNode,
+				Exports: sortedCopy(slice.Exports),
+			})
+
+			segments := append([]*model.FSDSegment{}, slice.Segments...)
+			sort.SliceStable(segments, func(i, j int) bool {
+				return segments[i].Name < segments[j].Name
+			})
+
+			for _, segment := range segments {
+				dir := segmentPath(slicePath, segment.Name)
+				segmentNode := nodeID(NodeSegment, path.Join(slicePath, segment.Name))
+				nodes = append(nodes, ReportNode{
+					ID:     segmentNode,
+					Kind:   NodeSegment,
+					Name:   segment.Name,
+					Path:   dir,
+					Parent: sliceNode,
+				})
+
+				for _, file := range sortedCopy(segment.Files) {
+					filePath := path.Join(dir, file)
+					stats := segment.Stats[file]
+					nodes = append(nodes, ReportNode{
+						ID:     nodeID(NodeFile, filePath),
+						Kind:   NodeFile,
+						Name:   file,
+						Path:   filePath,
+						Parent: segmentNode,
+						Lines:  stats.Lines,
+						Bytes:  stats.Bytes,
+					})
+				}
+			}
+		}
+	}
+
+	return nodes
+}
+
+func dependencyKey(fromFile string, line int, importPath, toFile string) string {
+	return strings.Join([]string{fromFile, strconv.Itoa(line), importPath, toFile}, "\x00")
+}
+
+func sortedDependencies(deps []dependencies.Dependency) []dependencies.Dependency {
+	sorted := append([]dependencies.Dependency{}, deps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.FromFile != b.FromFile {
+			return a.FromFile < b.FromFile
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.ImportPath != b.ImportPath {
+			return a.ImportPath < b.ImportPath
+		}
+		return a.ToFile < b.ToFile
+	})
+	return sorted
+}
+
+// ReportEdges строит ребра JSON-отчета для зависимостей в порядке отчета.
+// Через нее другие выводы (например, query --format json) описывают
+// зависимости так же, как поле edges.
+func ReportEdges(deps []dependencies.Dependency) []ReportEdge {
+	return buildReportEdges(sortedDependencies(deps))
+}
+
+// buildReportEdges строит ребра из отсортированных зависимостей. ID ребра —
+// файл, строка и путь импорта; совпадающие импорты получают суффикс #n.
+func buildReportEdges(deps []dependencies.Dependency) []ReportEdge {
+	edges := make([]ReportEdge, 0, len(deps))
+	seen := make(map[string]int)
+
+	for _, dep := range deps {
+		id := fmt.Sprintf("edge:%s:%d:%s", dep.FromFile, dep.Line, dep.ImportPath)
+		if n := seen[id]; n > 0 {
+			seen[id]++
+			id = fmt.Sprintf("%s#%d", id, n)
+		} else {
+			seen[id] = 1
+		}
+
+		edge := ReportEdge{
+			ID:          id,
+			From:        nodeID(NodeSlice, sliceID(dep.FromLayer, dep.FromSlice)),
+			To:          nodeID(NodeSlice, sliceID(dep.ToLayer, dep.ToSlice)),
+			Type:        string(dep.Type),
+			FromLayer:   dep.FromLayer,
+			FromSlice:   dep.FromSlice,
+			FromSegment: dep.FromSegment,
+			ToLayer:     dep.ToLayer,
+			ToSlice:     dep.ToSlice,
+			ToSegment:   dep.ToSegment,
+			FromFile:    dep.FromFile,
+			ToFile:      dep.ToFile,
+			ViaFile:     dep.ViaFile,
+			Line:        dep.Line,
+			ImportPath:  dep.ImportPath,
+			ImportKind:  string(dep.ImportKind),
+			Specifiers:  dep.Specifiers,
+		}
+		for _, origin := range dep.Origins {
+			edge.Origins = append(edge.Origins, ReportSymbolOrigin{
+				Name:    origin.Name,
+				File:    origin.File,
+				Layer:   origin.Layer,
+				Slice:   origin.Slice,
+				Segment: origin.Segment,
+				Chain:   emptyIfNil(origin.Chain),
+			})
+		}
+		edges = append(edges, edge)
+	}
+
+	return edges
+}
+
+func buildReportMetrics(doc *JSONReport) ReportMetrics {
+	metrics := ReportMetrics{Slices: []SliceMetrics{}}
+
+	files := make(map[string]int)
+	lines := make(map[string]int)
+	sizes := make(map[string]int64)
+	parents := make(map[string]string)
+	var slices []string
+	for _, node := range doc.Nodes {
+		parents[node.ID] = node.Parent
+		switch node.Kind {
+		case NodeLayer:
+			metrics.Totals.Layers++
+		case NodeSlice:
+			metrics.Totals.Slices++
+			slices = append(slices, node.ID)
+		case NodeSegment:
+			metrics.Totals.Segments++
+		case NodeFile:
+			metrics.Totals.Files++
+			metrics.Totals.Lines += node.Lines
+			metrics.Totals.Bytes += node.Bytes
+			slice := parents[node.Parent]
+			files[slice]++
+			lines[slice] += node.Lines
+			sizes[slice] += node.Bytes
+		}
+	}
+	metrics.Totals.Dependencies = len(doc.Edges)
+	metrics.Totals.Violations = len(doc.Violations)
+
+	fanIn := make(map[string]map[string]bool)
+	fanOut := make(map[string]map[string]bool)
+	importsIn := make(map[string]int)
+	importsOut := make(map[string]int)
+	for _, edge := range doc.Edges {
+		if edge.From == edge.To {
+			continue
+		}
+		if fanOut[edge.From] == nil {
+			fanOut[edge.From] = make(map[string]bool)
+		}
+		if fanIn[edge.To] == nil {
+			fanIn[edge.To] = make(map[string]bool)
+		}
+		fanOut[edge.From][edge.To] = true
+		fanIn[edge.To][edge.From] = true
+		importsOut[edge.From]++
+		importsIn[edge.To]++
+	}
+
+	sort.Strings(slices)
+	for _, slice := range slices {
+		sliceMetrics := SliceMetrics{
+			ID:         slice,
+			Files:      files[slice],
+			Lines:      lines[slice],
+			Bytes:      sizes[slice],
+			FanIn:      len(fanIn[slice]),
+			FanOut:     len(fanOut[slice]),
+			ImportsIn:  importsIn[slice],
+			ImportsOut: importsOut[slice],
+		}
+		if total := sliceMetrics.FanIn + sliceMetrics.FanOut; total > 0 {
+			sliceMetrics.Instability = math.Round(float64(sliceMetrics.FanOut)/float64(total)*1000) / 1000
+		}
+		metrics.Slices = append(metrics.Slices, sliceMetrics)
+	}
+
+	return metrics
+}
+
+// ReadJSONReport читает отчет формата json и проверяет совместимость
+// мажорной версии схемы.
+func ReadJSONReport(r io.Reader) (*JSONReport, error) {
+	var doc JSONReport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, i18n.Errorf("не удалось разобрать JSON-отчет: %v", err)
+	}
+
+	major := strings.SplitN(doc.SchemaVersion, ".", 2)[0]
+	if major != strings.SplitN(ReportSchemaVersion, ".", 2)[0] {
+		return nil, i18n.Errorf("неподдерживаемая версия схемы отчета %q (ожидается %s)", doc.SchemaVersion, ReportSchemaVersion)
+	}
+
+	return &doc, nil
+}
+
+// Structure восстанавливает структуру проекта и результаты анализа из отчета.
+func (doc *JSONReport) Structure() *model.ProjectStructure {
+	structure := &model.ProjectStructure{Layers: []*model.FSDLayer{}}
+
+	layers := make(map[string]*model.FSDLayer)
+	slices := make(map[string]*model.FSDSlice)
+	segments := make(map[string]*model.FSDSegment)
+
+	for _, node := range doc.Nodes {
+		switch node.Kind {
+		case NodeLayer:
+			layer := &model.FSDLayer{Name: node.Name, Slices: []*model.FSDSlice{}}
+			layers[node.ID] = layer
+			structure.Layers = append(structure.Layers, layer)
+		case NodeSlice:
+			if layer, ok := layers[node.Parent]; ok {
+				slice := &model.FSDSlice{Name: node.Name, Segments: []*model.FSDSegment{}, Exports: node.Exports}
+				slices[node.ID] = slice
+				layer.Slices = append(layer.Slices, slice)
+			}
+		case NodeSegment:
+			if slice, ok := slices[node.Parent]; ok {
+				segment := &model.FSDSegment{Name: node.Name, Files: []string{}}
+				segments[node.ID] = segment
+				slice.Segments = append(slice.Segments, segment)
+			}
+		case NodeFile:
+			if segment, ok := segments[node.Parent]; ok {
+				segment.Files = append(segment.Files, node.Name)
+				if segment.Stats == nil {
+					segment.Stats = make(map[string]model.FileStats)
+				}
+				segment.Stats[node.Name] = model.FileStats{Lines: node.Lines, Bytes: node.Bytes}
+			}
+		}
+	}
+
+	edges := make(map[string]dependencies.Dependency)
+	for _, edge := range doc.Edges {
+		dep := edge.dependency()
+		edges[edge.ID] = dep
+		structure.Dependencies = append(structure.Dependencies, dep)
+	}
+	for _, edge := range doc.InternalEdges {
+		dep := edge.dependency()
+		edges[edge.ID] = dep
+		structure.InternalImports = append(structure.InternalImports, dep)
+	}
+
+	for _, issue := range doc.Issues.PublicAPI {
+		structure.APIIssues = append(structure.APIIssues, dependencies.APIIssue{
+			Kind:  dependencies.APIIssueKind(issue.Kind),
+			Layer: issue.Layer,
+			Slice: issue.Slice,
+			Name:  issue.Name,
+			File:  issue.File,
+			Line:  issue.Line,
+		})
+	}
+
+	for _, issue := range doc.Issues.Barrels {
+		structure.BarrelIssues = append(structure.BarrelIssues, dependencies.BarrelIssue{
+			Kind:        dependencies.BarrelIssueKind(issue.Kind),
+			Layer:       issue.Layer,
+			Slice:       issue.Slice,
+			File:        issue.File,
+			Line:        issue.Line,
+			TargetLayer: issue.TargetLayer,
+			TargetSlice: issue.TargetSlice,
+			Chain:       issue.Chain,
+		})
+	}
+
+	for _, violation := range doc.Issues.SegmentViolations {
+		structure.SegmentViolations = append(structure.SegmentViolations, dependencies.SegmentViolation{
+			Rule:        config.SegmentRule{From: violation.Rule.From, To: violation.Rule.To, Scope: violation.Rule.Scope},
+			FromLayer:   violation.FromLayer,
+			FromSlice:   violation.FromSlice,
+			FromSegment: violation.FromSegment,
+			ToLayer:     violation.ToLayer,
+			ToSlice:     violation.ToSlice,
+			ToSegment:   violation.ToSegment,
+			File:        violation.File,
+			Line:        violation.Line,
+			ImportPath:  violation.ImportPath,
+		})
+	}
+
+	for _, cycle := range doc.Issues.SegmentCycles {
+		segmentCycle := dependencies.SegmentCycle{
+			Layer:    cycle.Layer,
+			Slice:    cycle.Slice,
+			Segments: cycle.Segments,
+		}
+		for _, id := range cycle.Edges {
+			if dep, ok := edges[id]; ok {
+				segmentCycle.Imports = append(segmentCycle.Imports, dep)
+			}
+		}
+		structure.SegmentCycles = append(structure.SegmentCycles, segmentCycle)
+	}
+
+	return structure
+}
+
+// dependency восстанавливает зависимость, из которой построено ребро.
+func (edge ReportEdge) dependency() dependencies.Dependency {
+	dep := dependencies.Dependency{
+		FromLayer:   edge.FromLayer,
+		FromSlice:   edge.FromSlice,
+		FromSegment: edge.FromSegment,
+		ToLayer:     edge.ToLayer,
+		ToSlice:     edge.ToSlice,
+		ToSegment:   edge.ToSegment,
+		Type:        dependencies.DependencyType(edge.Type),
+		FromFile:    edge.FromFile,
+		ToFile:      edge.ToFile,
+		ViaFile:     edge.ViaFile,
+		Line:        edge.Line,
+		ImportPath:  edge.ImportPath,
+		ImportKind:  dependencies.ImportKind(edge.ImportKind),
+		Specifiers:  edge.Specifiers,
+	}
+	for _, origin := range edge.Origins {
+		dep.Origins = append(dep.Origins, dependencies.SymbolOrigin{
+			Name:    origin.Name,
+			File:    origin.File,
+			Layer:   origin.Layer,
+			Slice:   origin.Slice,
+			Segment: origin.Segment,
+			Chain:   origin.Chain,
+		})
+	}
+	return dep
+}
+
+// ApplyConfig переносит в конфигурацию параметры анализа, сохраненные в
+// отчете, чтобы отчеты, построенные по нему, совпадали с исходным запуском.
+// Настройки вывода (outputDir, outputs, diagram и т. п.) не меняются.
+func (doc *JSONReport) ApplyConfig(cfg *config.Config) {
+	reportConfig := doc.Metadata.Config
+
+	cfg.SrcDir = reportConfig.SrcDir
+	cfg.ExcludeDirs = reportConfig.ExcludeDirs
+	cfg.AllowedCyclicalDependencies = reportConfig.AllowedCyclicalDependencies
+	cfg.MaxReexportDepth = reportConfig.MaxReexportDepth
+	if len(reportConfig.Layers) > 0 {
+		cfg.CustomLayers = reportConfig.Layers
+	}
+	if len(reportConfig.Aliases) > 0 {
+		cfg.Aliases = reportConfig.Aliases
+	}
+	cfg.SegmentRules = nil
+	for _, rule := range reportConfig.SegmentRules {
+		cfg.SegmentRules = append(cfg.SegmentRules, config.SegmentRule{From: rule.From, To: rule.To, Scope: rule.Scope})
+	}
+}
@@ -0,0 +1,133 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitExporter struct{}
+
+func init() {
+	Register(junitExporter{})
+}
+
+func (junitExporter) Name() string {
+	return "junit"
+}
+
+func (junitExporter) FileName() string {
+	return "fsd_structure.junit.xml"
+}
+
+func (junitExporter) Export(w io.Writer, report *Report) error {
+	return WriteJUnit(w, report.Structure, report.Config)
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func violationLocation(cfg *config.Config, violation Violation) string {
+	location := sourcePath(cfg, violation.File)
+	if violation.Line > 0 {
+		location += fmt.Sprintf(":%d", violation.Line)
+	}
+	return location
+}
+
+// WriteJUnit записывает нарушения как JUnit-отчет: по набору тестов на каждое
+// правило и по тесту на каждый слайс в нем. Тест слайса падает, если
+// импорты этого слайса нарушают правило; все нарушения перечисляются в
+// тексте failure.
+func WriteJUnit(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	var slices []string
+	for _, layer := range buildDependencyGraph(structure, false).Layers {
+		for _, slice := range layer.Slices {
+			slices = append(slices, slice.ID)
+		}
+	}
+
+	bySlice := make(map[string]map[string][]Violation)
+	for _, violation := range CollectViolations(structure) {
+		if bySlice[violation.RuleID] == nil {
+			bySlice[violation.RuleID] = make(map[string][]Violation)
+		}
+		from := sliceID(violation.Dependency.FromLayer, violation.Dependency.FromSlice)
+		bySlice[violation.RuleID][from] = append(bySlice[violation.RuleID][from], violation)
+	}
+
+	report := junitTestSuites{Name: toolName}
+	for _, rule := range localizedRules() {
+		suite := junitTestSuite{Name: rule.ID}
+
+		for _, slice := range slices {
+			testCase := junitTestCase{Name: slice, ClassName: rule.ID}
+
+			if violations := bySlice[rule.ID][slice]; len(violations) > 0 {
+				lines := make([]string, 0, len(violations))
+				for _, violation := range violations {
+					lines = append(lines, violationLocation(cfg, violation)+": "+violation.ReportMessage)
+				}
+				testCase.Failure = &junitFailure{
+					Message: i18n.ReportT("%s: нарушений — %d", rule.ShortDescription.Text, len(violations)),
+					Type:    rule.ID,
+					Text:    strings.Join(lines, "\n"),
+				}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		suite.Tests = len(suite.TestCases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Suites = append(report.Suites, suite)
+	}
+
+	return writeXML(w, report)
+}
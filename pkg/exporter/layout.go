@@ -0,0 +1,413 @@
+package exporter
+
+import (
+	"math"
+	"sort"
+
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	layoutNodeSpacing = 160.0
+	layoutBandHeight  = 120.0
+	layoutMinWidth    = 600.0
+	layoutSweeps      = 24
+	// layoutTransposePasses ограничивает перестановки соседних вершин за
+	// один проход барицентрического метода.
+	layoutTransposePasses = 4
+)
+
+// graphLayout — послойная раскладка графа слайсов для HTML-отчета: каждый
+// слой FSD — горизонтальная полоса в порядке model.KnownLayers, слайсы
+// внутри полосы упорядочены так, чтобы ребра пересекались как можно реже.
+type graphLayout struct {
+	Width  float64      `json:"width"`
+	Height float64      `json:"height"`
+	Bands  []layoutBand `json:"bands"`
+	Nodes  []layoutNode `json:"nodes"`
+	Edges  []layoutEdge `json:"edges"`
+}
+
+type layoutBand struct {
+	Layer  string  `json:"layer"`
+	Y      float64 `json:"y"`
+	Height float64 `json:"height"`
+}
+
+// layoutNode — слайс в раскладке. Files — файлы слайса для поиска в отчете.
+type layoutNode struct {
+	ID    string   `json:"id"`
+	Layer string   `json:"layer"`
+	X     float64  `json:"x"`
+	Y     float64  `json:"y"`
+	Files []string `json:"files"`
+}
+
+// layoutEdge — ребро между слайсами. Points — промежуточные точки ребер,
+// пересекающих несколько полос; Upward отмечает импорт из вышележащего слоя.
+type layoutEdge struct {
+	From    string       `json:"source"`
+	To      string       `json:"target"`
+	Type    string       `json:"type"`
+	Imports int          `json:"imports"`
+	Upward  bool         `json:"upward"`
+	Points  [][2]float64 `json:"points"`
+}
+
+// layoutVertex — слайс или фиктивная вершина длинного ребра в одной полосе.
+type layoutVertex struct {
+	id    string
+	rank  int
+	order float64
+	up    []*layoutVertex
+	down  []*layoutVertex
+	x     float64
+}
+
+// buildLayeredLayout строит раскладку по схеме Сугиямы: полосы задаются
+// порядком слоев, ребра через несколько полос разбиваются фиктивными
+// вершинами, порядок внутри полос подбирается барицентрическим методом.
+// Без order слайсы остаются в порядке объявления: так строится раскладка
+// для отчета, который открывается в силовой симуляции.
+func buildLayeredLayout(structure *model.ProjectStructure, order bool) *graphLayout {
+	g := buildDependencyGraph(structure, false)
+	layout := &graphLayout{Bands: []layoutBand{}, Nodes: []layoutNode{}, Edges: []layoutEdge{}}
+
+	ranks := make(map[string]int)
+	var rows [][]*layoutVertex
+	vertices := make(map[string]*layoutVertex)
+	files := make(map[string][]string)
+	for rank, layer := range g.Layers {
+		layout.Bands = append(layout.Bands, layoutBand{
+			Layer:  layer.Name,
+			Y:      float64(rank) * layoutBandHeight,
+			Height: layoutBandHeight,
+		})
+		var row []*layoutVertex
+		for i, slice := range layer.Slices {
+			v := &layoutVertex{id: slice.ID, rank: rank, order: float64(i)}
+			vertices[slice.ID] = v
+			ranks[slice.ID] = rank
+			files[slice.ID] = emptyIfNil(slice.Files)
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+
+	// Ребра направляются сверху вниз; длинные ребра проходят через
+	// фиктивные вершины в каждой промежуточной полосе.
+	chains := make([][]*layoutVertex, len(g.Edges))
+	for i, edge := range g.Edges {
+		from, to := vertices[edge.From], vertices[edge.To]
+		if from.rank == to.rank {
+			continue
+		}
+		if from.rank > to.rank {
+			from, to = to, from
+		}
+
+		chain := []*layoutVertex{from}
+		for rank := from.rank + 1; rank < to.rank; rank++ {
+			dummy := &layoutVertex{rank: rank, order: float64(len(rows[rank]))}
+			rows[rank] = append(rows[rank], dummy)
+			chain = append(chain, dummy)
+		}
+		chain = append(chain, to)
+
+		for j := 1; j < len(chain); j++ {
+			chain[j-1].down = append(chain[j-1].down, chain[j])
+			chain[j].up = append(chain[j].up, chain[j-1])
+		}
+		chains[i] = chain
+	}
+
+	if order {
+		orderRows(rows)
+	}
+	positionRows(rows, layout)
+
+	for _, row := range rows {
+		for _, v := range row {
+			if v.id == "" {
+				continue
+			}
+			layout.Nodes = append(layout.Nodes, layoutNode{
+				ID:    v.id,
+				Layer: layout.Bands[v.rank].Layer,
+				X:     v.x,
+				Y:     layout.Bands[v.rank].Y + layoutBandHeight/2,
+				Files: files[v.id],
+			})
+		}
+	}
+
+	for i, edge := range g.Edges {
+		layoutEdge := layoutEdge{
+			From:    edge.From,
+			To:      edge.To,
+			Type:    string(edge.Type),
+			Imports: len(edge.Imports),
+			Upward:  ranks[edge.From] > ranks[edge.To],
+			Points:  [][2]float64{},
+		}
+		chain := chains[i]
+		if len(chain) > 2 {
+			inner := chain[1 : len(chain)-1]
+			if layoutEdge.Upward {
+				inner = reversedVertices(inner)
+			}
+			for _, v := range inner {
+				layoutEdge.Points = append(layoutEdge.Points, [2]float64{v.x, layout.Bands[v.rank].Y + layoutBandHeight/2})
+			}
+		}
+		layout.Edges = append(layout.Edges, layoutEdge)
+	}
+
+	return layout
+}
+
+func reversedVertices(vertices []*layoutVertex) []*layoutVertex {
+	reversed := make([]*layoutVertex, len(vertices))
+	for i, v := range vertices {
+		reversed[len(vertices)-1-i] = v
+	}
+	return reversed
+}
+
+// orderRows чередует проходы сверху вниз и снизу вверх, упорядочивая
+// вершины полосы по среднему положению соседей в соседней полосе, затем
+// переставляет соседние вершины, пока это уменьшает число пересечений (не
+// больше layoutTransposePasses проходов), и оставляет порядок с наименьшим
+// числом пересечений.
+func orderRows(rows [][]*layoutVertex) {
+	best := snapshotOrder(rows)
+	bestCrossings := countCrossings(rows)
+
+	for sweep := 0; sweep < layoutSweeps && bestCrossings > 0; sweep++ {
+		// Каждая вторая пара проходов разрешает ничьи в обратном порядке,
+		// чтобы не застревать на одной и той же расстановке.
+		reverseTies := sweep%4 >= 2
+		if sweep%2 == 0 {
+			for rank := 1; rank < len(rows); rank++ {
+				sortByBarycenter(rows[rank], func(v *layoutVertex) []*layoutVertex { return v.up }, reverseTies)
+			}
+		} else {
+			for rank := len(rows) - 2; rank >= 0; rank-- {
+				sortByBarycenter(rows[rank], func(v *layoutVertex) []*layoutVertex { return v.down }, reverseTies)
+			}
+		}
+		transposeRows(rows)
+
+		if crossings := countCrossings(rows); crossings < bestCrossings {
+			bestCrossings = crossings
+			best = snapshotOrder(rows)
+		}
+	}
+
+	for rank, row := range best {
+		rows[rank] = row
+		for i, v := range row {
+			v.order = float64(i)
+		}
+	}
+}
+
+func snapshotOrder(rows [][]*layoutVertex) [][]*layoutVertex {
+	snapshot := make([][]*layoutVertex, len(rows))
+	for rank, row := range rows {
+		snapshot[rank] = append([]*layoutVertex{}, row...)
+	}
+	return snapshot
+}
+
+func sortByBarycenter(row []*layoutVertex, neighbors func(*layoutVertex) []*layoutVertex, reverseTies bool) {
+	barycenters := make(map[*layoutVertex]float64, len(row))
+	for _, v := range row {
+		adjacent := neighbors(v)
+		if len(adjacent) == 0 {
+			barycenters[v] = v.order
+			continue
+		}
+		sum := 0.0
+		for _, u := range adjacent {
+			sum += u.order
+		}
+		barycenters[v] = sum / float64(len(adjacent))
+	}
+
+	sort.SliceStable(row, func(i, j int) bool {
+		a, b := barycenters[row[i]], barycenters[row[j]]
+		if a == b && reverseTies {
+			return row[i].order > row[j].order
+		}
+		return a < b
+	})
+	for i, v := range row {
+		v.order = float64(i)
+	}
+}
+
+// transposeRows меняет местами соседние вершины полосы, если это уменьшает
+// число пересечений с соседними полосами. Так снимаются ничьи
+// барицентрического метода, на которых он останавливается. Для каждой
+// пары считаются только пересечения ребер этих двух вершин.
+func transposeRows(rows [][]*layoutVertex) {
+	for pass := 0; pass < layoutTransposePasses; pass++ {
+		improved := false
+		for _, row := range rows {
+			for i := 0; i+1 < len(row); i++ {
+				u, v := row[i], row[i+1]
+				if pairCrossings(v, u) < pairCrossings(u, v) {
+					swapVertices(row, i)
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			return
+		}
+	}
+}
+
+func swapVertices(row []*layoutVertex, i int) {
+	row[i], row[i+1] = row[i+1], row[i]
+	row[i].order, row[i+1].order = float64(i), float64(i+1)
+}
+
+// pairCrossings считает пересечения ребер вершин left и right, если left
+// стоит левее right, с обеими соседними полосами.
+func pairCrossings(left, right *layoutVertex) int {
+	return neighborCrossings(left.up, right.up) + neighborCrossings(left.down, right.down)
+}
+
+func neighborCrossings(left, right []*layoutVertex) int {
+	crossings := 0
+	for _, a := range left {
+		for _, b := range right {
+			if a.order > b.order {
+				crossings++
+			}
+		}
+	}
+	return crossings
+}
+
+// countCrossings считает пересечения ребер между соседними полосами.
+func countCrossings(rows [][]*layoutVertex) int {
+	crossings := 0
+	for rank := 0; rank+1 < len(rows); rank++ {
+		crossings += crossingsBelow(rows[rank])
+	}
+	return crossings
+}
+
+// crossingsBelow считает пересечения ребер между полосой и следующей за ней:
+// после сортировки ребер по верхнему концу пересечения — это инверсии по
+// нижнему концу, которые считаются сортировкой слиянием за O(E log E).
+func crossingsBelow(row []*layoutVertex) int {
+	var segments [][2]float64
+	for _, v := range row {
+		for _, u := range v.down {
+			segments = append(segments, [2]float64{v.order, u.order})
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i][0] != segments[j][0] {
+			return segments[i][0] < segments[j][0]
+		}
+		return segments[i][1] < segments[j][1]
+	})
+
+	lower := make([]float64, len(segments))
+	for i, segment := range segments {
+		lower[i] = segment[1]
+	}
+	return countInversions(lower, make([]float64, len(lower)))
+}
+
+// countInversions считает пары i < j с values[i] > values[j] и сортирует
+// values; buffer — рабочий массив той же длины.
+func countInversions(values, buffer []float64) int {
+	if len(values) < 2 {
+		return 0
+	}
+	mid := len(values) / 2
+	inversions := countInversions(values[:mid], buffer[:mid]) + countInversions(values[mid:], buffer[mid:])
+
+	i, j, k := 0, mid, 0
+	for i < mid && j < len(values) {
+		if values[j] < values[i] {
+			inversions += mid - i
+			buffer[k] = values[j]
+			j++
+		} else {
+			buffer[k] = values[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buffer[k:], values[i:mid])
+	copy(buffer[k:], values[j:])
+	copy(values, buffer)
+	return inversions
+}
+
+// positionRows задает координаты x: вершины полосы располагаются с равным
+// шагом, после чего несколько проходов сдвигают их к среднему положению
+// соседей, не нарушая порядок и минимальный шаг.
+func positionRows(rows [][]*layoutVertex, layout *graphLayout) {
+	widest := 0
+	for _, row := range rows {
+		if len(row) > widest {
+			widest = len(row)
+		}
+	}
+	layout.Width = math.Max(layoutMinWidth, float64(widest)*layoutNodeSpacing)
+	layout.Height = float64(len(rows)) * layoutBandHeight
+
+	for _, row := range rows {
+		offset := (layout.Width - float64(len(row)-1)*layoutNodeSpacing) / 2
+		for i, v := range row {
+			v.x = offset + float64(i)*layoutNodeSpacing
+		}
+	}
+
+	for pass := 0; pass < 4; pass++ {
+		for _, row := range rows {
+			desired := make([]float64, len(row))
+			for i, v := range row {
+				desired[i] = v.x
+				neighbors := append(append([]*layoutVertex{}, v.up...), v.down...)
+				if len(neighbors) == 0 {
+					continue
+				}
+				sum := 0.0
+				for _, u := range neighbors {
+					sum += u.x
+				}
+				desired[i] = sum / float64(len(neighbors))
+			}
+			placeRow(row, desired, layout.Width)
+		}
+	}
+}
+
+// placeRow ставит вершины как можно ближе к желаемым координатам, сохраняя
+// порядок, минимальный шаг и границы полосы.
+func placeRow(row []*layoutVertex, desired []float64, width float64) {
+	half := layoutNodeSpacing / 2
+	for i, v := range row {
+		x := math.Max(desired[i], half)
+		if i > 0 {
+			x = math.Max(x, row[i-1].x+layoutNodeSpacing)
+		}
+		v.x = x
+	}
+	for i := len(row) - 1; i >= 0; i-- {
+		limit := width - half
+		if i+1 < len(row) {
+			limit = row[i+1].x - layoutNodeSpacing
+		}
+		row[i].x = math.Min(row[i].x, limit)
+	}
+}
@@ -0,0 +1,239 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	defaultTopSlices     = 10
+	defaultMaxViolations = 50
+)
+
+type markdownExporter struct{}
+
+func init() {
+	Register(markdownExporter{})
+}
+
+func (markdownExporter) Name() string {
+	return "markdown"
+}
+
+func (markdownExporter) FileName() string {
+	return "fsd_structure.md"
+}
+
+func (markdownExporter) Export(w io.Writer, report *Report) error {
+	return writeMarkdown(w, report)
+}
+
+// WriteMarkdown записывает краткую сводку анализа в Markdown для
+// комментария к merge request.
+func WriteMarkdown(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	return writeMarkdown(w, NewReport(structure, cfg))
+}
+
+func markdownConfig(cfg *config.Config) config.MarkdownConfig {
+	var markdown config.MarkdownConfig
+	if cfg != nil {
+		markdown = cfg.Markdown
+	}
+	if markdown.TopSlices == 0 {
+		markdown.TopSlices = defaultTopSlices
+	}
+	if markdown.MaxViolations == 0 {
+		markdown.MaxViolations = defaultMaxViolations
+	}
+	return markdown
+}
+
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+
+func markdownCell(s string) string {
+	return markdownCellEscaper.Replace(s)
+}
+
+// markdownLocation возвращает "файл:строка" со ссылкой на файл в
+// репозитории, если задан markdown.repoURL.
+func markdownLocation(repoURL, file string, line int) string {
+	location := file
+	anchor := ""
+	if line > 0 {
+		location = fmt.Sprintf("%s:%d", file, line)
+		anchor = fmt.Sprintf("#L%d", line)
+	}
+	if repoURL == "" {
+		return "`" + markdownCell(location) + "`"
+	}
+	return fmt.Sprintf("[%s](%s/%s%s)", markdownCell(location), strings.TrimRight(repoURL, "/"), markdownPath(file), anchor)
+}
+
+// markdownPath экранирует каждый сегмент пути для URL, сохраняя "/".
+func markdownPath(file string) string {
+	segments := strings.Split(file, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func writeMarkdown(w io.Writer, report *Report) error {
+	opts := markdownConfig(report.Config)
+	doc := BuildJSONReport(report, reportTime())
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "## "+i18n.T("Отчет fsd-crawler"))
+	fmt.Fprintln(out)
+	if len(doc.Violations) == 0 {
+		fmt.Fprintln(out, i18n.T("✅ Нарушений не найдено."))
+	} else {
+		fmt.Fprintln(out, i18n.T("❌ Найдено нарушений: **%d**.", len(doc.Violations)))
+	}
+	fmt.Fprintln(out)
+	totals := doc.Metrics.Totals
+	fmt.Fprintln(out, i18n.T("Слоев: %d, слайсов: %d, сегментов: %d, файлов: %d, импортов: %d.",
+		totals.Layers, totals.Slices, totals.Segments, totals.Files, totals.Dependencies))
+
+	writeMarkdownLayers(out, doc)
+	writeMarkdownViolations(out, doc, report.Config, opts)
+	writeMarkdownTopSlices(out, doc, opts.TopSlices)
+
+	if opts.Mermaid {
+		var diagram bytes.Buffer
+		if err := WriteMermaid(&diagram, report.Structure, false, diagramConfig(report.Config)); err != nil {
+			return err
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "<details><summary>"+i18n.T("Граф зависимостей")+"</summary>")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "```mermaid")
+		out.Write(diagram.Bytes())
+		fmt.Fprintln(out, "```")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "</details>")
+	}
+
+	return out.Flush()
+}
+
+func writeMarkdownLayers(out io.Writer, doc *JSONReport) {
+	type layerCounts struct {
+		name                   string
+		slices, files, imports int
+	}
+
+	var layers []*layerCounts
+	byNode := make(map[string]*layerCounts)
+	for _, node := range doc.Nodes {
+		switch node.Kind {
+		case NodeLayer:
+			counts := &layerCounts{name: node.Name}
+			layers = append(layers, counts)
+			byNode[node.ID] = counts
+		case NodeSlice:
+			if counts, ok := byNode[node.Parent]; ok {
+				counts.slices++
+				byNode[node.ID] = counts
+			}
+		case NodeSegment:
+			if counts, ok := byNode[node.Parent]; ok {
+				byNode[node.ID] = counts
+			}
+		case NodeFile:
+			if counts, ok := byNode[node.Parent]; ok {
+				counts.files++
+			}
+		}
+	}
+	for _, edge := range doc.Edges {
+		if counts, ok := byNode[edge.From]; ok && edge.From != edge.To {
+			counts.imports++
+		}
+	}
+
+	if len(layers) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "### "+i18n.T("Слои"))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, i18n.T("| Слой | Слайсы | Файлы | Импорты из других слайсов |"))
+	fmt.Fprintln(out, "|------|-------:|------:|--------------------------:|")
+	for _, layer := range layers {
+		fmt.Fprintf(out, "| %s | %d | %d | %d |\n", markdownCell(layer.name), layer.slices, layer.files, layer.imports)
+	}
+}
+
+func writeMarkdownViolations(out io.Writer, doc *JSONReport, cfg *config.Config, opts config.MarkdownConfig) {
+	if len(doc.Violations) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "### "+i18n.T("Нарушения"))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, i18n.T("| Правило | Файл | Описание |"))
+	fmt.Fprintln(out, "|---------|------|----------|")
+
+	shown := doc.Violations
+	if opts.MaxViolations > 0 && len(shown) > opts.MaxViolations {
+		shown = shown[:opts.MaxViolations]
+	}
+	for _, violation := range shown {
+		fmt.Fprintf(out, "| `%s` | %s | %s |\n",
+			violation.Rule,
+			markdownLocation(opts.RepoURL, sourcePath(cfg, violation.File), violation.Line),
+			markdownCell(violation.Message))
+	}
+	if hidden := len(doc.Violations) - len(shown); hidden > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, i18n.T("…и еще %d. Полный список — в отчетах SARIF или JSON.", hidden))
+	}
+}
+
+// writeMarkdownTopSlices выводит слайсы с наибольшим числом связей с другими
+// слайсами (fan-in + fan-out).
+func writeMarkdownTopSlices(out io.Writer, doc *JSONReport, limit int) {
+	slices := make([]SliceMetrics, 0, len(doc.Metrics.Slices))
+	for _, slice := range doc.Metrics.Slices {
+		if slice.FanIn+slice.FanOut > 0 {
+			slices = append(slices, slice)
+		}
+	}
+	if len(slices) == 0 || limit < 0 {
+		return
+	}
+
+	sort.SliceStable(slices, func(i, j int) bool {
+		a, b := slices[i], slices[j]
+		if a.FanIn+a.FanOut != b.FanIn+b.FanOut {
+			return a.FanIn+a.FanOut > b.FanIn+b.FanOut
+		}
+		return a.ImportsIn+a.ImportsOut > b.ImportsIn+b.ImportsOut
+	})
+	if len(slices) > limit {
+		slices = slices[:limit]
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "### "+i18n.T("Самые связанные слайсы"))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, i18n.T("| Слайс | Fan-in | Fan-out | Импорты (вх./исх.) | Нестабильность |"))
+	fmt.Fprintln(out, "|-------|-------:|--------:|-------------------:|---------------:|")
+	for _, slice := range slices {
+		fmt.Fprintf(out, "| %s | %d | %d | %d / %d | %.2f |\n",
+			markdownCell(strings.TrimPrefix(slice.ID, NodeSlice+":")),
+			slice.FanIn, slice.FanOut, slice.ImportsIn, slice.ImportsOut, slice.Instability)
+	}
+}
@@ -0,0 +1,115 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+type mermaidExporter struct{}
+
+func init() {
+	Register(mermaidExporter{})
+}
+
+func (mermaidExporter) Name() string {
+	return "mermaid"
+}
+
+func (mermaidExporter) FileName() string {
+	return "fsd_structure.mmd"
+}
+
+func (mermaidExporter) Export(w io.Writer, report *Report) error {
+	return WriteMermaid(w, report.Structure, fileLevelGraph(report.Config), diagramConfig(report.Config))
+}
+
+func diagramConfig(cfg *config.Config) config.DiagramConfig {
+	if cfg == nil {
+		return config.DiagramConfig{}
+	}
+	return cfg.Diagram
+}
+
+// diagramNodeIDs назначает узлам короткие идентификаторы (n0, n1, ...),
+// допустимые и в Mermaid, и в PlantUML.
+func diagramNodeIDs(g *dependencyGraph) map[string]string {
+	ids := make(map[string]string)
+	for i, node := range g.nodeIDs() {
+		ids[node] = "n" + strconv.Itoa(i)
+	}
+	return ids
+}
+
+func mermaidLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+// WriteMermaid записывает граф слайсов в виде Mermaid flowchart: слои —
+// подграфы, слайсы (или файлы при fileLevel) — узлы, нарушения выделяются
+// стилем ребра. Граф фильтруется и ограничивается по настройкам opts.
+func WriteMermaid(w io.Writer, structure *model.ProjectStructure, fileLevel bool, opts config.DiagramConfig) error {
+	g := buildDependencyGraph(structure, fileLevel)
+	hiddenNodes, hiddenEdges := g.applyDiagramLimits(opts)
+	ids := diagramNodeIDs(g)
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "flowchart TB")
+	if hiddenNodes > 0 || hiddenEdges > 0 {
+		fmt.Fprintln(out, "  %% "+i18n.T("Скрыто узлов: %d, ребер: %d (настройки diagram)", hiddenNodes, hiddenEdges))
+	}
+
+	sliceIndex := 0
+	for i, layer := range g.Layers {
+		fmt.Fprintf(out, "  subgraph layer_%d[%s]\n", i, mermaidLabel(layer.Name))
+		for _, slice := range layer.Slices {
+			if !fileLevel {
+				fmt.Fprintf(out, "    %s[%s]\n", ids[slice.ID], mermaidLabel(slice.Name))
+				continue
+			}
+			fmt.Fprintf(out, "    subgraph slice_%d[%s]\n", sliceIndex, mermaidLabel(slice.Name))
+			sliceIndex++
+			for _, file := range slice.Files {
+				fmt.Fprintf(out, "      %s[%s]\n", ids[file], mermaidLabel(strings.TrimPrefix(file, slice.ID+"/")))
+			}
+			fmt.Fprintln(out, "    end")
+		}
+		fmt.Fprintln(out, "  end")
+	}
+
+	link := 0
+	for i := 0; i < len(g.Layers)-1; i++ {
+		fmt.Fprintf(out, "  layer_%d ~~~ layer_%d\n", i, i+1)
+		link++
+	}
+
+	var styles []string
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		if edge.Type == dependencies.DependencyTest {
+			arrow = "-.->"
+		}
+		if len(edge.Imports) > 1 {
+			arrow += "|" + strconv.Itoa(len(edge.Imports)) + "|"
+		}
+		fmt.Fprintf(out, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+
+		if edge.IsViolation() {
+			styles = append(styles, fmt.Sprintf("  linkStyle %d stroke:%s,stroke-width:3px", link, dependencyColor(edge.Type)))
+		}
+		link++
+	}
+
+	for _, style := range styles {
+		fmt.Fprintln(out, style)
+	}
+
+	return out.Flush()
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+type plantUMLExporter struct{}
+
+func init() {
+	Register(plantUMLExporter{})
+}
+
+func (plantUMLExporter) Name() string {
+	return "plantuml"
+}
+
+func (plantUMLExporter) FileName() string {
+	return "fsd_structure.puml"
+}
+
+func (plantUMLExporter) Export(w io.Writer, report *Report) error {
+	return WritePlantUML(w, report.Structure, fileLevelGraph(report.Config), diagramConfig(report.Config))
+}
+
+func plantUMLLabel(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+}
+
+// WritePlantUML записывает граф слайсов в виде диаграммы PlantUML: слои —
+// пакеты, слайсы (или файлы при fileLevel) — прямоугольники, нарушения
+// выделяются цветом и толщиной стрелки. Граф фильтруется и ограничивается
+// по настройкам opts.
+func WritePlantUML(w io.Writer, structure *model.ProjectStructure, fileLevel bool, opts config.DiagramConfig) error {
+	g := buildDependencyGraph(structure, fileLevel)
+	hiddenNodes, hiddenEdges := g.applyDiagramLimits(opts)
+	ids := diagramNodeIDs(g)
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "@startuml")
+	fmt.Fprintln(out, "top to bottom direction")
+	fmt.Fprintln(out, "skinparam packageStyle rectangle")
+	fmt.Fprintln(out, "skinparam shadowing false")
+	if hiddenNodes > 0 || hiddenEdges > 0 {
+		fmt.Fprintln(out, "' "+i18n.T("Скрыто узлов: %d, ребер: %d (настройки diagram)", hiddenNodes, hiddenEdges))
+	}
+
+	var anchors []string
+	sliceIndex := 0
+	for i, layer := range g.Layers {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "package %s as layer_%d {\n", plantUMLLabel(layer.Name), i)
+		for _, slice := range layer.Slices {
+			if !fileLevel {
+				fmt.Fprintf(out, "  rectangle %s as %s\n", plantUMLLabel(slice.Name), ids[slice.ID])
+				continue
+			}
+			fmt.Fprintf(out, "  package %s as slice_%d {\n", plantUMLLabel(slice.Name), sliceIndex)
+			sliceIndex++
+			for _, file := range slice.Files {
+				fmt.Fprintf(out, "    rectangle %s as %s\n", plantUMLLabel(strings.TrimPrefix(file, slice.ID+"/")), ids[file])
+			}
+			fmt.Fprintln(out, "  }")
+		}
+		fmt.Fprintln(out, "}")
+
+		first := layer.Slices[0].ID
+		if fileLevel {
+			first = layer.Slices[0].Files[0]
+		}
+		anchors = append(anchors, ids[first])
+	}
+
+	if len(anchors) > 1 || len(g.Edges) > 0 {
+		fmt.Fprintln(out)
+	}
+	for i := 0; i < len(anchors)-1; i++ {
+		fmt.Fprintf(out, "%s -[hidden]-> %s\n", anchors[i], anchors[i+1])
+	}
+
+	for _, edge := range g.Edges {
+		style := []string{dependencyColor(edge.Type)}
+		if edge.IsViolation() {
+			style = append(style, "bold")
+		}
+		if edge.Type == dependencies.DependencyTest {
+			style = append(style, "dashed")
+		}
+
+		line := fmt.Sprintf("%s -[%s]-> %s", ids[edge.From], strings.Join(style, ","), ids[edge.To])
+		if len(edge.Imports) > 1 {
+			line += " : " + strconv.Itoa(len(edge.Imports))
+		}
+		fmt.Fprintln(out, line)
+	}
+
+	fmt.Fprintln(out, "@enduml")
+
+	return out.Flush()
+}
@@ -0,0 +1,288 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "fsd-crawler"
+	toolURI      = "https://github.com/falkomerr/fsd-crawler"
+)
+
+const (
+	RuleUpwardImport     = "fsd/upward-import"
+	RuleCrossSliceImport = "fsd/cross-slice-import"
+	RulePublicAPIBypass  = "fsd/public-api-bypass"
+	RuleCycle            = "fsd/cycle"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	HelpURI              string             `json:"helpUri"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// violationRules описывает правила, по которым формируются нарушения для
+// SARIF и других форматов для CI. Порядок определяет ruleIndex.
+var violationRules = []sarifRule{
+	{
+		ID:                   RuleUpwardImport,
+		Name:                 "UpwardImport",
+		ShortDescription:     sarifMessage{Text: "Импорт из вышележащего слоя"},
+		FullDescription:      sarifMessage{Text: "Модуль может импортировать только из слоев, расположенных ниже него (app → processes → pages → widgets → features → entities → shared)."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+		HelpURI:              "https://feature-sliced.design/docs/reference/layers",
+	},
+	{
+		ID:                   RuleCrossSliceImport,
+		Name:                 "CrossSliceImport",
+		ShortDescription:     sarifMessage{Text: "Импорт между слайсами одного слоя"},
+		FullDescription:      sarifMessage{Text: "Слайсы одного слоя не должны импортировать друг друга."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+		HelpURI:              "https://feature-sliced.design/docs/reference/slices-segments",
+	},
+	{
+		ID:                   RulePublicAPIBypass,
+		Name:                 "PublicAPIBypass",
+		ShortDescription:     sarifMessage{Text: "Импорт в обход публичного API"},
+		FullDescription:      sarifMessage{Text: "Другие слайсы должны импортировать модуль только через его публичный API (index-файл в корне слайса)."},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		HelpURI:              "https://feature-sliced.design/docs/reference/public-api",
+	},
+	{
+		ID:                   RuleCycle,
+		Name:                 "Cycle",
+		ShortDescription:     sarifMessage{Text: "Циклическая зависимость между слайсами"},
+		FullDescription:      sarifMessage{Text: "Импорт входит в цикл зависимостей между слайсами."},
+		DefaultConfiguration: sarifConfiguration{Level: "error"},
+		HelpURI:              toolURI + "#readme",
+	},
+}
+
+// localizedRules возвращает violationRules с описаниями на локали отчетов
+// для CI.
+func localizedRules() []sarifRule {
+	rules := make([]sarifRule, len(violationRules))
+	for i, rule := range violationRules {
+		rule.ShortDescription.Text = i18n.ReportT(rule.ShortDescription.Text)
+		rule.FullDescription.Text = i18n.ReportT(rule.FullDescription.Text)
+		rules[i] = rule
+	}
+	return rules
+}
+
+// Violation — нарушение правил FSD, привязанное к конкретному импорту.
+// Message переведено на текущую локаль для отчетов, которые читает человек;
+// ReportMessage — тот же текст для отчетов CI (SARIF, JUnit, Checkstyle,
+// Code Quality, GitHub Actions) на явно выбранной локали, чтобы их
+// содержимое не зависело от LANG машины.
+type Violation struct {
+	RuleID        string
+	Message       string
+	ReportMessage string
+	File          string
+	Line          int
+	Dependency    dependencies.Dependency
+}
+
+func newViolation(ruleID string, dep dependencies.Dependency, format string, args ...interface{}) Violation {
+	return Violation{
+		RuleID:        ruleID,
+		Message:       i18n.T(format, args...),
+		ReportMessage: i18n.ReportT(format, args...),
+		File:          dep.FromFile,
+		Line:          dep.Line,
+		Dependency:    dep,
+	}
+}
+
+func ruleIndex(ruleID string) int {
+	for i, rule := range violationRules {
+		if rule.ID == ruleID {
+			return i
+		}
+	}
+	return -1
+}
+
+func ruleLevel(ruleID string) string {
+	if i := ruleIndex(ruleID); i >= 0 {
+		return violationRules[i].DefaultConfiguration.Level
+	}
+	return "warning"
+}
+
+// CollectViolations собирает нарушения из зависимостей структуры в порядке
+// правил: импорты из вышележащих слоев, импорты между слайсами одного слоя,
+// обход публичного API и импорты, входящие в циклы.
+func CollectViolations(structure *model.ProjectStructure) []Violation {
+	deps := structureDependencies(structure)
+	var violations []Violation
+
+	for _, dep := range deps {
+		from := sliceID(dep.FromLayer, dep.FromSlice)
+		to := sliceID(dep.ToLayer, dep.ToSlice)
+
+		switch {
+		case dep.Type == dependencies.DependencyCyclical:
+			violations = append(violations, newViolation(RuleUpwardImport, dep,
+				"Слой %s не должен импортировать из вышележащего слоя %s (%s → %s)", dep.FromLayer, dep.ToLayer, from, to))
+		case dep.Type == dependencies.DependencySameLayer && from != to:
+			violations = append(violations, newViolation(RuleCrossSliceImport, dep,
+				"Слайс %s не должен импортировать слайс %s того же слоя", from, to))
+		}
+	}
+
+	for _, dep := range deps {
+		if dependencies.BypassesPublicAPI(dep) {
+			violations = append(violations, newViolation(RulePublicAPIBypass, dep,
+				"Импорт %q обходит публичный API слайса %s", dep.ImportPath, sliceID(dep.ToLayer, dep.ToSlice)))
+		}
+	}
+
+	for _, cycle := range dependencies.FindCycles(deps) {
+		slices := strings.Join(cycle.Slices, ", ")
+		for _, dep := range cycle.Imports {
+			violations = append(violations, newViolation(RuleCycle, dep,
+				"Импорт %s → %s входит в цикл между слайсами %s",
+				sliceID(dep.FromLayer, dep.FromSlice), sliceID(dep.ToLayer, dep.ToSlice), slices))
+		}
+	}
+
+	return violations
+}
+
+// sourcePath переводит путь файла относительно srcDir в путь относительно
+// корня репозитория, в котором запускается анализ.
+func sourcePath(cfg *config.Config, file string) string {
+	if cfg == nil || cfg.SrcDir == "" {
+		return file
+	}
+	return path.Join(filepath.ToSlash(filepath.Clean(cfg.SrcDir)), file)
+}
+
+type sarifExporter struct{}
+
+func init() {
+	Register(sarifExporter{})
+}
+
+func (sarifExporter) Name() string {
+	return "sarif"
+}
+
+func (sarifExporter) FileName() string {
+	return "fsd_structure.sarif"
+}
+
+func (sarifExporter) Export(w io.Writer, report *Report) error {
+	return WriteSARIF(w, report.Structure, report.Config)
+}
+
+// WriteSARIF записывает нарушения в формате SARIF 2.1.0: по одному правилу на
+// тип нарушения и по одному результату на импорт, с указанием файла и строки.
+func WriteSARIF(w io.Writer, structure *model.ProjectStructure, cfg *config.Config) error {
+	results := []sarifResult{}
+	for _, violation := range CollectViolations(structure) {
+		location := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: sourcePath(cfg, violation.File)},
+		}
+		if violation.Line > 0 {
+			location.Region = &sarifRegion{StartLine: violation.Line}
+		}
+
+		results = append(results, sarifResult{
+			RuleID:    violation.RuleID,
+			RuleIndex: ruleIndex(violation.RuleID),
+			Level:     ruleLevel(violation.RuleID),
+			Message:   sarifMessage{Text: violation.ReportMessage},
+			Locations: []sarifLocation{{PhysicalLocation: location}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           toolName,
+				InformationURI: toolURI,
+				Rules:          localizedRules(),
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(log)
+}
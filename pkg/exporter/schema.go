@@ -0,0 +1,199 @@
+package exporter
+
+import (
+	_ "embed"
+)
+
+// ReportSchemaVersion — версия схемы JSON-отчета. Мажорная версия меняется
+// при несовместимых изменениях, минорная — при добавлении полей.
+const ReportSchemaVersion = "1.2.0"
+
+const reportSchemaURI = "https://raw.githubusercontent.com/falkomerr/fsd-crawler/main/pkg/exporter/schema/report.schema.json"
+
+// ReportSchema — JSON Schema отчета формата json.
+//
+//go:embed schema/report.schema.json
+var ReportSchema []byte
+
+const (
+	NodeLayer   = "layer"
+	NodeSlice   = "slice"
+	NodeSegment = "segment"
+	NodeFile    = "file"
+)
+
+// JSONReport — документ формата json. Все списки упорядочены
+// детерминированно, поэтому отчеты двух запусков можно сравнивать диффом.
+// InternalEdges — относительные импорты внутри слайсов: они не входят в
+// Edges и метрики, но нужны для циклов сегментов и путей между файлами.
+type JSONReport struct {
+	Schema        string            `json:"$schema"`
+	SchemaVersion string            `json:"schemaVersion"`
+	Metadata      ReportMetadata    `json:"metadata"`
+	Nodes         []ReportNode      `json:"nodes"`
+	Edges         []ReportEdge      `json:"edges"`
+	InternalEdges []ReportEdge      `json:"internalEdges"`
+	Violations    []ReportViolation `json:"violations"`
+	Issues        ReportIssues      `json:"issues"`
+	Metrics       ReportMetrics     `json:"metrics"`
+}
+
+type ReportMetadata struct {
+	Tool        string       `json:"tool"`
+	GeneratedAt string       `json:"generatedAt"`
+	Config      ReportConfig `json:"config"`
+}
+
+// ReportConfig — параметры конфигурации, с которыми выполнялся анализ.
+type ReportConfig struct {
+	SrcDir                      string              `json:"srcDir"`
+	Layers                      []string            `json:"layers"`
+	ExcludeDirs                 []string            `json:"excludeDirs"`
+	Aliases                     map[string]string   `json:"aliases"`
+	AllowedCyclicalDependencies []string            `json:"allowedCyclicalDependencies"`
+	MaxReexportDepth            int                 `json:"maxReexportDepth"`
+	SegmentRules                []ReportSegmentRule `json:"segmentRules"`
+}
+
+type ReportSegmentRule struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Scope string `json:"scope,omitempty"`
+}
+
+// ReportNode — узел иерархии слой → слайс → сегмент → файл. Идентификатор
+// имеет вид "<kind>:<path>" и не зависит от порядка обхода. Lines и Bytes
+// заполняются для файлов.
+type ReportNode struct {
+	ID      string   `json:"id"`
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Parent  string   `json:"parent,omitempty"`
+	Exports []string `json:"exports,omitempty"`
+	Lines   int      `json:"lines,omitempty"`
+	Bytes   int64    `json:"bytes,omitempty"`
+}
+
+// ReportEdge — один импорт. From и To ссылаются на узлы слайсов. Импорт
+// через barrel-файл приписывается модулю, где объявлены имена, а ViaFile
+// хранит barrel-файл из импорта.
+type ReportEdge struct {
+	ID          string               `json:"id"`
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	Type        string               `json:"type"`
+	FromLayer   string               `json:"fromLayer"`
+	FromSlice   string               `json:"fromSlice"`
+	FromSegment string               `json:"fromSegment,omitempty"`
+	ToLayer     string               `json:"toLayer"`
+	ToSlice     string               `json:"toSlice"`
+	ToSegment   string               `json:"toSegment,omitempty"`
+	FromFile    string               `json:"fromFile,omitempty"`
+	ToFile      string               `json:"toFile,omitempty"`
+	ViaFile     string               `json:"viaFile,omitempty"`
+	Line        int                  `json:"line,omitempty"`
+	ImportPath  string               `json:"importPath,omitempty"`
+	ImportKind  string               `json:"importKind,omitempty"`
+	Specifiers  []string             `json:"specifiers,omitempty"`
+	Origins     []ReportSymbolOrigin `json:"origins,omitempty"`
+}
+
+type ReportSymbolOrigin struct {
+	Name    string   `json:"name"`
+	File    string   `json:"file"`
+	Layer   string   `json:"layer"`
+	Slice   string   `json:"slice"`
+	Segment string   `json:"segment"`
+	Chain   []string `json:"chain"`
+}
+
+// ReportViolation — нарушение одного из правил violationRules. ID совпадает
+// с отпечатком нарушения в формате codequality.
+type ReportViolation struct {
+	ID       string `json:"id"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Edge     string `json:"edge"`
+}
+
+type ReportIssues struct {
+	PublicAPI         []ReportAPIIssue         `json:"publicApi"`
+	Barrels           []ReportBarrelIssue      `json:"barrels"`
+	SegmentViolations []ReportSegmentViolation `json:"segmentViolations"`
+	SegmentCycles     []ReportSegmentCycle     `json:"segmentCycles"`
+}
+
+type ReportAPIIssue struct {
+	Kind  string `json:"kind"`
+	Layer string `json:"layer"`
+	Slice string `json:"slice"`
+	Name  string `json:"name"`
+	File  string `json:"file"`
+	Line  int    `json:"line,omitempty"`
+}
+
+type ReportBarrelIssue struct {
+	Kind        string   `json:"kind"`
+	Layer       string   `json:"layer"`
+	Slice       string   `json:"slice"`
+	File        string   `json:"file"`
+	Line        int      `json:"line,omitempty"`
+	TargetLayer string   `json:"targetLayer,omitempty"`
+	TargetSlice string   `json:"targetSlice,omitempty"`
+	Chain       []string `json:"chain,omitempty"`
+}
+
+type ReportSegmentViolation struct {
+	Rule        ReportSegmentRule `json:"rule"`
+	FromLayer   string            `json:"fromLayer"`
+	FromSlice   string            `json:"fromSlice"`
+	FromSegment string            `json:"fromSegment"`
+	ToLayer     string            `json:"toLayer"`
+	ToSlice     string            `json:"toSlice"`
+	ToSegment   string            `json:"toSegment"`
+	File        string            `json:"file"`
+	Line        int               `json:"line,omitempty"`
+	ImportPath  string            `json:"importPath"`
+}
+
+type ReportSegmentCycle struct {
+	Layer    string   `json:"layer"`
+	Slice    string   `json:"slice"`
+	Segments []string `json:"segments"`
+	Edges    []string `json:"edges"`
+}
+
+type ReportMetrics struct {
+	Totals ReportTotals   `json:"totals"`
+	Slices []SliceMetrics `json:"slices"`
+}
+
+type ReportTotals struct {
+	Layers       int   `json:"layers"`
+	Slices       int   `json:"slices"`
+	Segments     int   `json:"segments"`
+	Files        int   `json:"files"`
+	Lines        int   `json:"lines"`
+	Bytes        int64 `json:"bytes"`
+	Dependencies int   `json:"dependencies"`
+	Violations   int   `json:"violations"`
+}
+
+// SliceMetrics — метрики связности слайса: FanIn и FanOut считают
+// различные слайсы, ImportsIn и ImportsOut — отдельные импорты.
+// Instability = FanOut / (FanIn + FanOut).
+type SliceMetrics struct {
+	ID          string  `json:"id"`
+	Files       int     `json:"files"`
+	Lines       int     `json:"lines"`
+	Bytes       int64   `json:"bytes"`
+	FanIn       int     `json:"fanIn"`
+	FanOut      int     `json:"fanOut"`
+	ImportsIn   int     `json:"importsIn"`
+	ImportsOut  int     `json:"importsOut"`
+	Instability float64 `json:"instability"`
+}
@@ -0,0 +1,140 @@
+package exporter
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultSourceMaxBytes — лимит исходного кода, встраиваемого в HTML-отчет
+// для предпросмотра мест импорта.
+const defaultSourceMaxBytes = 1 << 20
+
+// HTMLSourceRoute — адрес, по которому локальный сервер отчета (serveHTML)
+// отдает файлы из srcDir для предпросмотра исходного кода.
+const HTMLSourceRoute = "/source/"
+
+// NewSourceHandler возвращает обработчик HTMLSourceRoute для локального
+// сервера отчета. Он отдает только файлы, вошедшие в отчет (файлы структуры
+// и импортирующие файлы); остальное содержимое srcDir, например .env или
+// конфигурации, отвечает 404.
+func NewSourceHandler(report *Report) http.Handler {
+	allowed := make(map[string]bool)
+	for _, node := range BuildJSONReport(report, time.Time{}).Nodes {
+		if node.Kind == NodeFile {
+			allowed[node.Path] = true
+		}
+	}
+	for _, file := range sourceFiles(report) {
+		allowed[file] = true
+	}
+
+	srcDir := "."
+	if report.Config != nil && report.Config.SrcDir != "" {
+		srcDir = report.Config.SrcDir
+	}
+
+	return http.StripPrefix(HTMLSourceRoute, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if !allowed[file] {
+			http.NotFound(w, r)
+			return
+		}
+
+		content, err := os.Open(filepath.Join(srcDir, filepath.FromSlash(file)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer content.Close()
+		info, err := content.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		http.ServeContent(w, r, "", info.ModTime(), content)
+	}))
+}
+
+func sourceMaxBytes(report *Report) int {
+	if report.Config == nil || report.Config.HTML.SourceMaxBytes == 0 {
+		return defaultSourceMaxBytes
+	}
+	return report.Config.HTML.SourceMaxBytes
+}
+
+// sourceFiles возвращает файлы с местами импорта в порядке встраивания:
+// сначала файлы с нарушениями, затем файлы с проблемами публичного API,
+// реэкспортов и сегментов, затем остальные импортирующие файлы.
+func sourceFiles(report *Report) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(group []string) {
+		sort.Strings(group)
+		for _, file := range group {
+			if file != "" && !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+
+	var violations []string
+	for _, violation := range CollectViolations(report.Structure) {
+		violations = append(violations, violation.File)
+	}
+	add(violations)
+
+	var issues []string
+	for _, issue := range report.APIIssues {
+		issues = append(issues, issue.File)
+	}
+	for _, issue := range report.BarrelIssues {
+		issues = append(issues, issue.File)
+	}
+	for _, violation := range report.SegmentViolations {
+		issues = append(issues, violation.File)
+	}
+	add(issues)
+
+	var imports []string
+	for _, dep := range report.Dependencies {
+		imports = append(imports, dep.FromFile)
+	}
+	add(imports)
+
+	return files
+}
+
+// collectSources читает исходный код файлов с местами импорта для
+// встраивания в HTML-отчет. Суммарный размер ограничен html.sourceMaxBytes
+// (-1 отключает встраивание); файлы, которые не помещаются в оставшийся
+// лимит или не читаются (например, при render из сохраненного JSON),
+// пропускаются — их можно открыть через локальный сервер отчета.
+func collectSources(report *Report) map[string]string {
+	sources := make(map[string]string)
+	budget := sourceMaxBytes(report)
+	if budget < 0 || report.Config == nil {
+		return sources
+	}
+
+	for _, file := range sourceFiles(report) {
+		path := filepath.Join(report.Config.SrcDir, filepath.FromSlash(file))
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > int64(budget) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil || len(content) > budget {
+			continue
+		}
+		sources[file] = string(content)
+		budget -= len(content)
+	}
+	return sources
+}
@@ -0,0 +1,266 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"fsd-crawler/pkg/config"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+// HTMLTemplateData — данные HTML-шаблона, стандартного или пользовательского
+// (htmlTemplatePath). Report — полный отчет в формате json: узлы вплоть до
+// файлов, импорты, нарушения, проблемы и метрики; Violations и Metrics —
+// его поля для краткости. Graph и FileGraph — граф зависимостей слайсов и
+// файлов, сгруппированный по слоям. History — метрики по коммитам из
+// fsd_history.json в outputDir (команда history), от старых к новым.
+// Config — конфигурация анализа, GeneratedAt — время построения отчета
+// (учитывает SOURCE_DATE_EPOCH).
+type HTMLTemplateData struct {
+	Layers                      []*model.FSDLayer
+	Dependencies                []dependencies.Dependency
+	HasDependencies             bool
+	AllowedCyclicalDependencies []string
+	APIIssues                   []dependencies.APIIssue
+	BarrelIssues                []dependencies.BarrelIssue
+	SegmentViolations           []dependencies.SegmentViolation
+	SegmentCycles               []dependencies.SegmentCycle
+
+	Report      *JSONReport
+	Violations  []ReportViolation
+	Metrics     ReportMetrics
+	Graph       *dependencyGraph
+	FileGraph   *dependencyGraph
+	DSM         *dsmMatrix
+	History     []HistoryEntry
+	Config      *config.Config
+	GeneratedAt time.Time
+	Locale      string
+
+	Assets     HTMLAssets
+	ReportData htmlReportData
+}
+
+func buildHTMLTemplateData(report *Report) (*HTMLTemplateData, error) {
+	cfg := report.Config
+	if cfg == nil {
+		cfg = &config.DefaultConfig
+	}
+
+	assets, err := buildHTMLAssets(report.Config)
+	if err != nil {
+		return nil, err
+	}
+	layoutMode, err := htmlLayoutMode(report.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := reportHistory(report)
+	if err != nil {
+		return nil, err
+	}
+
+	generatedAt := reportTime()
+	reportData := buildHTMLReportData(report, cfg.AllowedCyclicalDependencies, layoutMode)
+	reportData.History = history
+	doc := BuildJSONReport(report, generatedAt)
+
+	return &HTMLTemplateData{
+		Layers:                      report.Structure.Layers,
+		Dependencies:                report.Dependencies,
+		HasDependencies:             len(report.Dependencies) > 0,
+		AllowedCyclicalDependencies: cfg.AllowedCyclicalDependencies,
+		APIIssues:                   report.APIIssues,
+		BarrelIssues:                report.BarrelIssues,
+		SegmentViolations:           report.SegmentViolations,
+		SegmentCycles:               report.SegmentCycles,
+		Report:                      doc,
+		Violations:                  doc.Violations,
+		Metrics:                     doc.Metrics,
+		Graph:                       buildDependencyGraph(report.Structure, false),
+		FileGraph:                   buildDependencyGraph(report.Structure, true),
+		DSM:                         buildDSM(report.Structure),
+		History:                     history,
+		Config:                      cfg,
+		GeneratedAt:                 generatedAt,
+		Locale:                      i18n.Locale(),
+		Assets:                      assets,
+		ReportData:                  reportData,
+	}, nil
+}
+
+// isAllowedCyclical сообщает, разрешена ли циклическая зависимость
+// настройкой allowedCyclicalDependencies: в списке указан слайс или слой
+// одной из сторон импорта.
+func isAllowedCyclical(allowed []string, dep dependencies.Dependency) bool {
+	if dep.Type != dependencies.DependencyCyclical {
+		return false
+	}
+	for _, entry := range allowed {
+		switch entry {
+		case sliceID(dep.FromLayer, dep.FromSlice), sliceID(dep.ToLayer, dep.ToSlice), dep.FromLayer, dep.ToLayer:
+			return true
+		}
+	}
+	return false
+}
+
+// htmlTemplateFuncs возвращает функции HTML-шаблонов отчета:
+//
+//	t "Слой: %s" .Name      — перевод подписи на текущую локаль
+//	locale                  — текущая локаль (ru или en)
+//	json .Metrics           — значение в JSON
+//	where .Violations "Rule" "fsd/cycle" — элементы списка, у которых поле
+//	                          (или ключ, в том числе составной "Rule.From")
+//	                          равно значению
+//	pathJoin "src" .File, pathBase, pathDir — работа с путями через "/"
+//	sliceID .FromLayer .FromSlice — идентификатор слайса, как в отчетах
+//	allowedCyclical .       — зависимость разрешена allowedCyclicalDependencies
+//	dict "key" value ...    — отображение для передачи нескольких значений
+//	                          в блок {{template}}
+//	join, contains, hasPrefix, hasSuffix — функции пакета strings
+func htmlTemplateFuncs(report *Report) template.FuncMap {
+	var allowed []string
+	if report.Config != nil {
+		allowed = report.Config.AllowedCyclicalDependencies
+	}
+
+	return template.FuncMap{
+		"t":      i18n.T,
+		"locale": i18n.Locale,
+		"json":   templateJSON,
+		"where":  templateWhere,
+		"pathJoin": func(elem ...string) string {
+			return path.Join(elem...)
+		},
+		"pathBase": path.Base,
+		"pathDir":  path.Dir,
+		"sliceID":  sliceID,
+		"allowedCyclical": func(dep dependencies.Dependency) bool {
+			return isAllowedCyclical(allowed, dep)
+		},
+		"dict":      templateDict,
+		"join":      strings.Join,
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"hasSuffix": strings.HasSuffix,
+	}
+}
+
+func templateJSON(value interface{}) (template.JS, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return template.JS(data), nil
+}
+
+func templateWhere(list interface{}, field string, value interface{}) ([]interface{}, error) {
+	items := reflect.ValueOf(list)
+	if !items.IsValid() {
+		return nil, nil
+	}
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return nil, i18n.Errorf("where: ожидается список, получено %s", items.Kind())
+	}
+
+	expected := fmt.Sprint(value)
+	var result []interface{}
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i)
+		if actual, ok := templateField(item, field); ok && fmt.Sprint(actual) == expected {
+			result = append(result, item.Interface())
+		}
+	}
+	return result, nil
+}
+
+// templateField возвращает значение поля структуры или ключа отображения;
+// составное имя "Rule.From" проходит по вложенным значениям.
+func templateField(value reflect.Value, name string) (interface{}, bool) {
+	for _, part := range strings.Split(name, ".") {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, false
+			}
+			value = value.Elem()
+		}
+		switch {
+		case value.Kind() == reflect.Struct:
+			value = value.FieldByName(part)
+		case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String:
+			value = value.MapIndex(reflect.ValueOf(part).Convert(value.Type().Key()))
+		default:
+			return nil, false
+		}
+		if !value.IsValid() || !value.CanInterface() {
+			return nil, false
+		}
+	}
+	return value.Interface(), true
+}
+
+func templateDict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, i18n.Errorf("dict: ожидается четное число аргументов")
+	}
+	dict := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, i18n.Errorf("dict: ключ %v не является строкой", pairs[i])
+		}
+		dict[key] = pairs[i+1]
+	}
+	return dict, nil
+}
+
+// parseHTMLTemplate собирает шаблон страницы: функции htmlTemplateFuncs,
+// блок "dsm" и блоки стандартного отчета, сам шаблон content и файлы
+// html.partials. Блоки из файлов html.partials подключаются последними и
+// переопределяют одноименные блоки стандартного отчета.
+func parseHTMLTemplate(report *Report, name, content string) (*template.Template, error) {
+	t := template.New(name).Funcs(htmlTemplateFuncs(report))
+	if err := parseDSMTemplate(t); err != nil {
+		return nil, err
+	}
+	if _, err := t.New("partials").Parse(htmlPartialsTemplate); err != nil {
+		return nil, i18n.Errorf("ошибка при парсинге HTML шаблона: %v", err)
+	}
+	if _, err := t.Parse(content); err != nil {
+		return nil, i18n.Errorf("ошибка при парсинге HTML шаблона: %v", err)
+	}
+
+	if report.Config == nil {
+		return t, nil
+	}
+	for _, pattern := range report.Config.HTML.Partials {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, i18n.Errorf("некорректный шаблон html.partials %q: %v", pattern, err)
+		}
+		if len(files) == 0 {
+			return nil, i18n.Errorf("html.partials: нет файлов по шаблону %q", pattern)
+		}
+		for _, file := range files {
+			partial, err := os.ReadFile(file)
+			if err != nil {
+				return nil, i18n.Errorf("не удалось прочитать HTML шаблон %s: %v", file, err)
+			}
+			if _, err := t.New(filepath.Base(file)).Parse(string(partial)); err != nil {
+				return nil, i18n.Errorf("ошибка при парсинге HTML шаблона %s: %v", file, err)
+			}
+		}
+	}
+	return t, nil
+}
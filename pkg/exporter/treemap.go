@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"time"
+)
+
+// treemapNode — узел иерархии слой → слайс → сегмент → файл для treemap и
+// sunburst HTML-отчета. Размеры и число нарушений родителя — суммы по
+// дочерним узлам; Instability берется из метрик слайса и наследуется его
+// сегментами и файлами.
+type treemapNode struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Kind        string         `json:"kind"`
+	Layer       string         `json:"layer"`
+	Files       int            `json:"files"`
+	Lines       int            `json:"lines"`
+	Bytes       int64          `json:"bytes"`
+	Violations  int            `json:"violations"`
+	Instability float64        `json:"instability"`
+	Children    []*treemapNode `json:"children,omitempty"`
+}
+
+func buildTreemap(report *Report) *treemapNode {
+	doc := BuildJSONReport(report, time.Time{})
+
+	violations := make(map[string]int)
+	for _, violation := range doc.Violations {
+		violations[nodeID(NodeFile, violation.File)]++
+	}
+	instability := make(map[string]float64)
+	for _, slice := range doc.Metrics.Slices {
+		instability[slice.ID] = slice.Instability
+	}
+
+	root := &treemapNode{ID: "", Name: "src", Kind: "root"}
+	nodes := map[string]*treemapNode{"": root}
+	for _, node := range doc.Nodes {
+		parent, ok := nodes[node.Parent]
+		if !ok {
+			continue
+		}
+
+		child := &treemapNode{ID: node.ID, Name: node.Name, Kind: node.Kind, Layer: parent.Layer}
+		switch node.Kind {
+		case NodeLayer:
+			child.Layer = node.Name
+		case NodeSlice:
+			child.Instability = instability[node.ID]
+		case NodeFile:
+			child.Files = 1
+			child.Lines = node.Lines
+			child.Bytes = node.Bytes
+			child.Violations = violations[node.ID]
+		}
+		if node.Kind != NodeSlice {
+			child.Instability = parent.Instability
+		}
+
+		parent.Children = append(parent.Children, child)
+		nodes[node.ID] = child
+	}
+
+	root.sum()
+	return root
+}
+
+func (n *treemapNode) sum() {
+	for _, child := range n.Children {
+		child.sum()
+		n.Files += child.Files
+		n.Lines += child.Lines
+		n.Bytes += child.Bytes
+		n.Violations += child.Violations
+	}
+}
@@ -0,0 +1,253 @@
+package i18n
+
+// en — английские переводы сообщений. Ключ — исходная русская строка;
+// глаголы форматирования (%s, %d, %v, %q) и подстановки скриптов отчета
+// ({0}, {1}...) должны совпадать с ключом.
+var en = map[string]string{
+	// Локаль и общие сообщения командной строки
+	"неподдерживаемая локаль %q (ожидается %s)":                                  "unsupported locale %q (expected %s)",
+	"флаг --locale требует значение (%s)":                                        "flag --locale requires a value (%s)",
+	"Неподдерживаемый формат вывода: %s":                                         "Unsupported output format: %s",
+	"Ошибка при экспорте в %s: %v":                                               "Failed to export %s: %v",
+	"Ошибка при запуске веб-сервера: %v":                                         "Failed to start the web server: %v",
+	"Предупреждение: не удалось загрузить конфигурацию: %v":                      "Warning: failed to load the configuration: %v",
+	"Используются значения по умолчанию.":                                        "Using default values.",
+	"Предупреждение: %v":                                                         "Warning: %v",
+	"Не удалось автоматически открыть браузер. Пожалуйста, откройте %s вручную.": "Could not open a browser automatically. Please open %s manually.",
+	"Ошибка при открытии браузера: %v":                                           "Failed to open the browser: %v",
+	"Ошибка при кодировании в JSON: %v":                                          "Failed to encode JSON: %v",
+	"Ошибка при записи CSV: %v":                                                  "Failed to write CSV: %v",
+	"Ошибка при генерации HTML: %v":                                              "Failed to generate HTML: %v",
+	"Отчет сохранен в %s":                                                        "Report saved to %s",
+
+	// Команда affected
+	"взять измененные файлы из git diff --name-only относительно указанной ветки или коммита и неотслеживаемые файлы": "take changed files from git diff --name-only against the given branch or commit, plus untracked files",
+	"вывести результат в формате JSON":                                     "print the result as JSON",
+	"Использование: fsd-crawler affected [--base REF] [--json] [файлы...]": "Usage: fsd-crawler affected [--base REF] [--json] [files...]",
+	"Без файлов и --base список измененных файлов читается из stdin.":      "Without files and --base, the list of changed files is read from stdin.",
+	"Ошибка при получении списка измененных файлов: %v":                    "Failed to get the list of changed files: %v",
+	"Ошибка при обработке путей: %v":                                       "Failed to process paths: %v",
+	"не удалось определить корень git-репозитория: %v":                     "failed to determine the git repository root: %v",
+	"не удалось выполнить git diff относительно %s: %v":                    "failed to run git diff against %s: %v",
+	"не удалось получить список неотслеживаемых файлов: %v":                "failed to list untracked files: %v",
+	"Измененные файлы":                                                     "Changed files",
+	"Затронутые файлы":                                                     "Affected files",
+	"Затронутые слайсы":                                                    "Affected slices",
+	"Затронутые страницы":                                                  "Affected pages",
+
+	// Команда query и язык запросов
+	"формат вывода: text, json или csv — найденные связи, любой формат отчетов (html, dot, mermaid, sarif...) — отчет только с найденными зависимостями": "output format: text, json or csv for the matched links, or any report format (html, dot, mermaid, sarif...) for a report with only the matched dependencies",
+	"Использование: fsd-crawler query [--format FORMAT] <выражение>":                  "Usage: fsd-crawler query [--format FORMAT] <expression>",
+	"Пример: fsd-crawler query 'from:features/* to:entities/* type:cyclical count>3'": "Example: fsd-crawler query 'from:features/* to:entities/* type:cyclical count>3'",
+	"Ключи: from, to, layer, slice, type, file, kind, count (>, >=, <, <=, =, !=).":   "Keys: from, to, layer, slice, type, file, kind, count (>, >=, <, <=, =, !=).",
+	"Ошибка в запросе: %v":                                                            "Query error: %v",
+	"Найдено связей: %d (импортов: %d)":                                               "Links found: %d (imports: %d)",
+	"некорректное условие %q: %v":                                                     "invalid condition %q: %v",
+	"некорректное условие %q: ожидается ключ:значение":                                "invalid condition %q: expected key:value",
+	"неизвестный ключ %q":                                                             "unknown key %q",
+	"некорректный шаблон %q: %v":                                                      "invalid pattern %q: %v",
+	"ожидается число":                                                                 "a number is expected",
+	"ожидается оператор сравнения (>, >=, <, <=, =, !=)":                              "a comparison operator is expected (>, >=, <, <=, =, !=)",
+
+	// Команда render
+	"форматы вывода через запятую (по умолчанию outputFormats из конфигурации)":                  "comma-separated output formats (outputFormats from the configuration by default)",
+	"директория для отчетов (по умолчанию outputDir из конфигурации)":                            "directory for reports (outputDir from the configuration by default)",
+	"Использование: fsd-crawler render [--format html,dot,...] [--out DIR] [fsd_structure.json]": "Usage: fsd-crawler render [--format html,dot,...] [--out DIR] [fsd_structure.json]",
+	"Строит отчеты по сохраненному JSON-отчету без повторного анализа исходников.":               "Builds reports from a saved JSON report without analyzing the sources again.",
+	"Без аргумента читается fsd_structure.json из outputDir.":                                    "Without an argument, fsd_structure.json is read from outputDir.",
+	"Ошибка при чтении отчета: %v":                                                               "Failed to read the report: %v",
+	"Ошибка при чтении отчета %s: %v":                                                            "Failed to read the report %s: %v",
+
+	// Команда schema
+	"Использование: fsd-crawler schema":                    "Usage: fsd-crawler schema",
+	"Выводит JSON Schema отчета формата json (версия %s).": "Prints the JSON Schema of the json report format (version %s).",
+	"не удалось вывести схему: %v":                         "failed to print the schema: %v",
+
+	// Команда history
+	"число последних коммитов для анализа":                                                "number of recent commits to analyze",
+	"ветка или коммит, с которого начинается история":                                     "branch or commit the history starts from",
+	"пересчитать метрики коммитов, уже сохраненных в истории":                             "recompute metrics of commits already saved in the history",
+	"Использование: fsd-crawler history [-n N] [--ref REF] [--rebuild] [--json]":          "Usage: fsd-crawler history [-n N] [--ref REF] [--rebuild] [--json]",
+	"Анализирует последние коммиты git-репозитория и сохраняет метрики в %s в outputDir.": "Analyzes recent commits of the git repository and saves their metrics to %s in outputDir.",
+	"HTML-отчет показывает по этому файлу график динамики.":                               "The HTML report shows a trend chart based on this file.",
+	"Ошибка при построении истории: %v":                                                   "Failed to build the history: %v",
+	"Ошибка при анализе коммита %s: %v":                                                   "Failed to analyze commit %s: %v",
+	"Предупреждение: в коммите %s нет директории %s, коммит пропущен":                     "Warning: commit %s has no directory %s, skipping it",
+	"История сохранена в %s":                                                              "History saved to %s",
+	"директория %s находится вне git-репозитория %s":                                      "directory %s is outside the git repository %s",
+	"не удалось получить список коммитов %s: %v":                                          "failed to list commits of %s: %v",
+	"не удалось создать временную директорию: %v":                                         "failed to create a temporary directory: %v",
+	"не удалось выполнить git archive: %v":                                                "failed to run git archive: %v",
+	"не удалось выполнить git archive: %v: %s":                                            "failed to run git archive: %v: %s",
+	"не удалось прочитать архив коммита: %v":                                              "failed to read the commit archive: %v",
+	"некорректный путь в архиве коммита: %s":                                              "invalid path in the commit archive: %s",
+	"не удалось прочитать историю %s: %v":                                                 "failed to read the history %s: %v",
+	"не удалось разобрать историю %s: %v":                                                 "failed to parse the history %s: %v",
+	"неподдерживаемая версия истории %q (ожидается %s)":                                   "unsupported history version %q (expected %s)",
+	"не удалось записать историю %s: %v":                                                  "failed to write the history %s: %v",
+	"Коммит":   "Commit",
+	"Дата":     "Date",
+	"Файлы":    "Files",
+	"Связи":    "Links",
+	"Описание": "Subject",
+
+	// Команда why
+	"вывести только k кратчайших путей (0 — все пути, их число растет экспоненциально)": "print only the k shortest paths (0 for all paths, whose number grows exponentially)",
+	"Использование: fsd-crawler why [-k N] <откуда> <куда>":                             "Usage: fsd-crawler why [-k N] <from> <to>",
+	"Узлы задаются как слайсы (pages/home) или файлы (pages/home/ui/HomePage.tsx).":     "Nodes are slices (pages/home) or files (pages/home/ui/HomePage.tsx).",
+	"%s не зависит от %s":        "%s does not depend on %s",
+	"%s → %s: найдено путей: %d": "%s → %s: paths found: %d",
+
+	// Конфигурация
+	"не удалось получить текущую директорию: %v":     "failed to get the current directory: %v",
+	"не удалось прочитать файл конфигурации %s: %v":  "failed to read the configuration file %s: %v",
+	"не удалось распарсить файл конфигурации %s: %v": "failed to parse the configuration file %s: %v",
+
+	// Экспорт отчетов
+	"ошибка при кодировании в JSON: %v":                                               "failed to encode JSON: %v",
+	"ошибка при парсинге шаблона DSM: %v":                                             "failed to parse the DSM template: %v",
+	"ошибка при парсинге HTML шаблона: %v":                                            "failed to parse the HTML template: %v",
+	"ошибка при генерации HTML: %v":                                                   "failed to generate HTML: %v",
+	"неподдерживаемый формат вывода: %s":                                              "unsupported output format: %s",
+	"не удалось подготовить ресурсы отчета: %v":                                       "failed to prepare report assets: %v",
+	"не удалось создать директорию для ресурсов: %v":                                  "failed to create the assets directory: %v",
+	"не удалось записать ресурс %s: %v":                                               "failed to write asset %s: %v",
+	"файл %s уже существует (overwrite: never)":                                       "file %s already exists (overwrite: never)",
+	"неизвестная политика перезаписи %q (ожидается always или never)":                 "unknown overwrite policy %q (expected always or never)",
+	"не удалось создать директорию для вывода: %v":                                    "failed to create the output directory: %v",
+	"не удалось создать файл %s: %v":                                                  "failed to create file %s: %v",
+	"не удалось записать файл %s: %v":                                                 "failed to write file %s: %v",
+	"для внешнего экспортера %s не указана команда":                                   "no command is set for external exporter %s",
+	"внешний экспортер %s завершился с ошибкой: %v":                                   "external exporter %s failed: %v",
+	"неизвестная раскладка html.layout %q (ожидается layered или force)":              "unknown html.layout %q (expected layered or force)",
+	"неизвестный режим html.assets %q (ожидается inline или external)":                "unknown html.assets mode %q (expected inline or external)",
+	"неизвестная область segmentRules.scope %q в %s (ожидается all, slice или cross)": "unknown segmentRules.scope %q in %s (expected all, slice or cross)",
+	"не удалось прочитать ресурс отчета %s: %v":                                       "failed to read report asset %s: %v",
+	"не удалось прочитать пользовательский HTML шаблон: %v":                           "failed to read the custom HTML template: %v",
+	"не удалось прочитать HTML шаблон %s: %v":                                         "failed to read HTML template %s: %v",
+	"ошибка при парсинге HTML шаблона %s: %v":                                         "failed to parse HTML template %s: %v",
+	"некорректный шаблон html.partials %q: %v":                                        "invalid html.partials pattern %q: %v",
+	"html.partials: нет файлов по шаблону %q":                                         "html.partials: no files match %q",
+	"where: ожидается список, получено %s":                                            "where: a list is expected, got %s",
+	"dict: ожидается четное число аргументов":                                         "dict: an even number of arguments is expected",
+	"dict: ключ %v не является строкой":                                               "dict: key %v is not a string",
+	"не удалось разобрать JSON-отчет: %v":                                             "failed to parse the JSON report: %v",
+	"неподдерживаемая версия схемы отчета %q (ожидается %s)":                          "unsupported report schema version %q (expected %s)",
+	"Скрыто узлов: %d, ребер: %d (настройки diagram)":                                 "Hidden nodes: %d, edges: %d (diagram settings)",
+
+	// Правила и нарушения
+	"Импорт из вышележащего слоя": "Import from a higher layer",
+	"Модуль может импортировать только из слоев, расположенных ниже него (app → processes → pages → widgets → features → entities → shared).": "A module may only import from layers below it (app → processes → pages → widgets → features → entities → shared).",
+	"Импорт между слайсами одного слоя":                      "Import between slices of the same layer",
+	"Слайсы одного слоя не должны импортировать друг друга.": "Slices of the same layer must not import each other.",
+	"Импорт в обход публичного API":                          "Import bypassing the public API",
+	"Другие слайсы должны импортировать модуль только через его публичный API (index-файл в корне слайса).": "Other slices must import a module only through its public API (the index file at the slice root).",
+	"Циклическая зависимость между слайсами":                                                                "Cyclic dependency between slices",
+	"Импорт входит в цикл зависимостей между слайсами.":                                                     "The import is part of a dependency cycle between slices.",
+	"Слой %s не должен импортировать из вышележащего слоя %s (%s → %s)":                                     "Layer %s must not import from the higher layer %s (%s → %s)",
+	"Слайс %s не должен импортировать слайс %s того же слоя":                                                "Slice %s must not import slice %s of the same layer",
+	"Импорт %q обходит публичный API слайса %s":                                                             "Import %q bypasses the public API of slice %s",
+	"Импорт %s → %s входит в цикл между слайсами %s":                                                        "Import %s → %s is part of a cycle between slices %s",
+	"%s: нарушений — %d": "%s: %d violations",
+
+	// Markdown-сводка
+	"Отчет fsd-crawler":            "fsd-crawler report",
+	"✅ Нарушений не найдено.":      "✅ No violations found.",
+	"❌ Найдено нарушений: **%d**.": "❌ Violations found: **%d**.",
+	"Слоев: %d, слайсов: %d, сегментов: %d, файлов: %d, импортов: %d.": "Layers: %d, slices: %d, segments: %d, files: %d, imports: %d.",
+	"Граф зависимостей": "Dependency graph",
+	"| Слой | Слайсы | Файлы | Импорты из других слайсов |": "| Layer | Slices | Files | Imports from other slices |",
+	"Нарушения": "Violations",
+	"| Правило | Файл | Описание |":                                      "| Rule | File | Description |",
+	"…и еще %d. Полный список — в отчетах SARIF или JSON.":               "…and %d more. The full list is in the SARIF or JSON reports.",
+	"Самые связанные слайсы":                                             "Most coupled slices",
+	"| Слайс | Fan-in | Fan-out | Импорты (вх./исх.) | Нестабильность |": "| Slice | Fan-in | Fan-out | Imports (in/out) | Instability |",
+
+	// HTML-отчет и матрица зависимостей
+	"Объем кода":           "Code volume",
+	"Вид:":                 "View:",
+	"Размер:":              "Size:",
+	"Цвет:":                "Color:",
+	"строки":               "lines",
+	"байты":                "bytes",
+	"файлы":                "files",
+	"слой":                 "layer",
+	"нарушения":            "violations",
+	"нестабильность":       "instability",
+	"Слой: %s":             "Layer: %s",
+	"Слайс: %s":            "Slice: %s",
+	"Сегмент: %s":          "Segment: %s",
+	"%d строк, %d байт":    "%d lines, %d bytes",
+	"Нет файлов":           "No files",
+	"Динамика по коммитам": "Trend by commit",
+	"Метрика:":             "Metric:",
+	"зависимости":          "dependencies",
+	"слайсы":               "slices",
+	"коммитов: %d":         "commits: %d",
+	"Нет сегментов":        "No segments",
+	"Публичный API":        "Public API",
+	"Нет слайсов":          "No slices",
+	"FSD структура не обнаружена": "No FSD structure found",
+	"Проблемы публичного API":     "Public API issues",
+	"экспорт": "export",
+	"не используется другими слайсами":                  "is not used by other slices",
+	"импортируется в обход публичного API":              "is imported bypassing the public API",
+	"Реэкспорты (barrel-файлы)":                         "Re-exports (barrel files)",
+	"глубокая цепочка export *":                         "deep export * chain",
+	"реэкспортирует слайс %s/%s":                        "re-exports slice %s/%s",
+	"Нарушения правил сегментов":                        "Segment rule violations",
+	"сегмент %s не должен импортировать %s":             "segment %s must not import %s",
+	"внутри слайса":                                     "within the slice",
+	"из других слайсов":                                 "from other slices",
+	"Циклы между сегментами внутри слайса":              "Cycles between segments within a slice",
+	"Зависимости между слоями и слайсами":               "Dependencies between layers and slices",
+	"Слайсы с разрешенными циклическими зависимостями:": "Slices with allowed cyclic dependencies:",
+	"поиск по слайсу или файлу":                         "search by slice or file",
+	"фокус:":              "focus:",
+	"например pages/home": "e.g. pages/home",
+	"соседей:":            "neighbours:",
+	"Сбросить":            "Reset",
+	"Типы:":               "Types:",
+	"нормальные":          "normal",
+	"на том же слое":      "same layer",
+	"циклические":         "cyclical",
+	"тестовые":            "test",
+	"Слои:":               "Layers:",
+	"Слои":                "Layers",
+	"Граф сил":            "Force graph",
+	"Список зависимостей": "Dependency list",
+	"разрешенная циклическая зависимость":   "allowed cyclic dependency",
+	"нормальная зависимость":                "normal dependency",
+	"зависимость на том же слое":            "same-layer dependency",
+	"циклическая зависимость":               "cyclic dependency",
+	"тестовая зависимость":                  "test dependency",
+	"Почему один слайс зависит от другого?": "Why does one slice depend on another?",
+	"откуда, например pages/home":           "from, e.g. pages/home",
+	"куда, например entities/user":          "to, e.g. entities/user",
+	"кратчайших путей:":                     "shortest paths:",
+	"Найти пути":                            "Find paths",
+	"Зависимости не обнаружены":             "No dependencies found",
+	"Матрица зависимостей (DSM)":            "Dependency structure matrix (DSM)",
+	"Строки — импортирующие слайсы, столбцы — импортируемые, в клетках — число импортов.": "Rows are importing slices, columns are imported slices, cells hold the number of imports.",
+	"Клетки ниже диагонали — импорты из нижележащего слоя в вышележащий.":                 "Cells below the diagonal are imports from a lower layer into a higher one.",
+	"Нажмите на клетку, чтобы увидеть места импорта.":                                     "Click a cell to see the import sites.",
+
+	// Подписи скриптов HTML-отчета
+	" (свернутый слой, слайсов: {0})":                   " (collapsed layer, slices: {0})",
+	" (разрешены циклические зависимости)":              " (cyclic dependencies allowed)",
+	"показано {0} из {1}":                               "showing {0} of {1}",
+	"развернуть":                                        "expand",
+	"свернуть":                                          "collapse",
+	"{0} → {1}: найдено путей: {2}":                     "{0} → {1}: paths found: {2}",
+	"{0} не зависит от {1}":                             "{0} does not depend on {1}",
+	"{0} → {1}: импортов: {2}":                          "{0} → {1}: imports: {2}",
+	"строк: {0}, байт: {1}, файлов: {2}":                "lines: {0}, bytes: {1}, files: {2}",
+	"нарушений: {0}":                                    "violations: {0}",
+	"нестабильность слайса: {0}":                        "slice instability: {0}",
+	"файл не встроен в отчет (см. html.sourceMaxBytes)": "the file is not embedded in the report (see html.sourceMaxBytes)",
+	"сервер вернул {0}":                                 "the server returned {0}",
+	"Исходный код недоступен: {0}":                      "Source code is unavailable: {0}",
+	"на уровень выше":                                   "up one level",
+	"Нет данных":                                        "No data",
+	"всего нарушений":                                   "total violations",
+}
@@ -0,0 +1,148 @@
+// Package i18n — каталог сообщений fsd-crawler. Ключами каталога служат
+// исходные русские строки (как в gettext), поэтому для локали ru перевод не
+// нужен, а непереведенная строка выводится как есть.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	LocaleRu = "ru"
+	LocaleEn = "en"
+)
+
+// DefaultLocale — локаль, если она не задана флагом, конфигурацией или
+// переменными окружения.
+const DefaultLocale = LocaleRu
+
+// catalogs — переводы исходных строк для локалей, отличных от ru.
+var catalogs = map[string]map[string]string{
+	LocaleEn: en,
+}
+
+var locale = DefaultLocale
+
+// reportLocale — локаль отчетов для CI. Ее задает только явный выбор (флаг
+// или конфигурация), а не переменные окружения: LANG машины, на которой
+// запущен анализ, не должен менять содержимое отчетов.
+var reportLocale = DefaultLocale
+
+// Locales возвращает поддерживаемые локали.
+func Locales() []string {
+	locales := []string{LocaleRu}
+	for name := range catalogs {
+		locales = append(locales, name)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Normalize приводит имя локали к поддерживаемому: "en_US.UTF-8" и "EN"
+// становятся "en". Второе значение — false, если язык не поддерживается.
+func Normalize(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == LocaleRu {
+		return name, true
+	}
+	if _, ok := catalogs[name]; ok {
+		return name, true
+	}
+	return "", false
+}
+
+// SetLocale выбирает локаль сообщений и отчетов для CI.
+func SetLocale(name string) error {
+	normalized, ok := Normalize(name)
+	if !ok {
+		return Errorf("неподдерживаемая локаль %q (ожидается %s)", name, strings.Join(Locales(), ", "))
+	}
+	locale = normalized
+	reportLocale = normalized
+	return nil
+}
+
+// SetEnvironmentLocale выбирает локаль сообщений, определенную по
+// переменным окружения (см. Detect). Локаль отчетов для CI не меняется.
+func SetEnvironmentLocale(name string) error {
+	normalized, ok := Normalize(name)
+	if !ok {
+		return Errorf("неподдерживаемая локаль %q (ожидается %s)", name, strings.Join(Locales(), ", "))
+	}
+	locale = normalized
+	return nil
+}
+
+// Locale возвращает текущую локаль.
+func Locale() string {
+	return locale
+}
+
+// Detect выбирает локаль: первое поддерживаемое из явно заданных значений
+// (флаг, конфигурация), затем LC_ALL, LC_MESSAGES и LANG. Если ни одно не
+// подходит, возвращается DefaultLocale.
+func Detect(configured ...string) string {
+	for _, name := range configured {
+		if normalized, ok := Normalize(name); ok {
+			return normalized
+		}
+	}
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		// Первая непустая переменная определяет язык, как в POSIX
+		if normalized, ok := Normalize(value); ok {
+			return normalized
+		}
+		break
+	}
+	return DefaultLocale
+}
+
+// T переводит сообщение на текущую локаль и подставляет аргументы, как
+// fmt.Sprintf. Без аргументов сообщение возвращается без форматирования.
+func T(message string, args ...interface{}) string {
+	return translate(locale, message, args...)
+}
+
+func translate(locale, message string, args ...interface{}) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		message = translated
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// ReportT — T для отчетов CI: переводит на явно выбранную локаль (флаг или
+// конфигурация) или оставляет исходный текст.
+func ReportT(message string, args ...interface{}) string {
+	return translate(reportLocale, message, args...)
+}
+
+// Errorf — fmt.Errorf с переводом формата на текущую локаль.
+func Errorf(format string, args ...interface{}) error {
+	return errors.New(T(format, args...))
+}
+
+// Messages возвращает переводы сообщений на текущую локаль для передачи в
+// скрипты отчетов. Непереведенные сообщения в результат не попадают.
+func Messages(keys []string) map[string]string {
+	messages := make(map[string]string)
+	for _, key := range keys {
+		if translated, ok := catalogs[locale][key]; ok {
+			messages[key] = translated
+		}
+	}
+	return messages
+}
@@ -0,0 +1,182 @@
+package i18n
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"en":          LocaleEn,
+		"EN":          LocaleEn,
+		"en_US.UTF-8": LocaleEn,
+		"en-GB":       LocaleEn,
+		"ru_RU.UTF-8": LocaleRu,
+		"de_DE":       "",
+		"C":           "",
+		"":            "",
+	}
+	for name, expected := range cases {
+		normalized, ok := Normalize(name)
+		if normalized != expected || ok != (expected != "") {
+			t.Errorf("Normalize(%q) = %q, %v; expected %q", name, normalized, ok, expected)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+
+	if locale := Detect(); locale != LocaleEn {
+		t.Errorf("Detect() from LANG = %q, expected en", locale)
+	}
+	if locale := Detect("", "ru"); locale != LocaleRu {
+		t.Errorf("Detect with configured ru = %q, expected ru", locale)
+	}
+
+	// LC_ALL важнее LANG, даже если его язык не поддерживается
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	if locale := Detect(); locale != DefaultLocale {
+		t.Errorf("Detect() with LC_ALL=de_DE = %q, expected %q", locale, DefaultLocale)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	if err := SetLocale("de"); err == nil {
+		t.Error("SetLocale(de) should fail")
+	}
+	if message := T("Слой: %s", "shared"); message != "Слой: shared" {
+		t.Errorf("ru message = %q", message)
+	}
+
+	if err := SetLocale("en_US.UTF-8"); err != nil {
+		t.Fatal(err)
+	}
+	if Locale() != LocaleEn {
+		t.Errorf("Locale() = %q, expected en", Locale())
+	}
+	if message := T("Слой: %s", "shared"); message != "Layer: shared" {
+		t.Errorf("en message = %q", message)
+	}
+	if message := T("нет такого сообщения"); message != "нет такого сообщения" {
+		t.Errorf("untranslated message = %q", message)
+	}
+	if err := Errorf("неизвестный ключ %q", "foo"); err.Error() != `unknown key "foo"` {
+		t.Errorf("Errorf = %q", err)
+	}
+
+	messages := Messages([]string{"показано {0} из {1}", "нет такого сообщения"})
+	if len(messages) != 1 || messages["показано {0} из {1}"] != "showing {0} of {1}" {
+		t.Errorf("Messages = %v", messages)
+	}
+	if message := ReportT("Слой: %s", "shared"); message != "Layer: shared" {
+		t.Errorf("ReportT with an explicit locale = %q", message)
+	}
+}
+
+func TestEnvironmentLocale(t *testing.T) {
+	defer SetLocale(DefaultLocale)
+
+	if err := SetEnvironmentLocale("en_US.UTF-8"); err != nil {
+		t.Fatal(err)
+	}
+	if message := T("Слой: %s", "shared"); message != "Layer: shared" {
+		t.Errorf("T with the environment locale = %q", message)
+	}
+	if message := ReportT("Слой: %s", "shared"); message != "Слой: shared" {
+		t.Errorf("ReportT must ignore the environment locale, got %q", message)
+	}
+}
+
+var placeholderPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]|\{\d+\}`)
+
+func placeholders(message string) string {
+	return strings.Join(placeholderPattern.FindAllString(message, -1), " ")
+}
+
+// TestCatalogPlaceholders проверяет, что перевод принимает те же аргументы,
+// что и исходное сообщение.
+func TestCatalogPlaceholders(t *testing.T) {
+	for key, translated := range en {
+		if placeholders(key) != placeholders(translated) {
+			t.Errorf("placeholders of %q (%s) and %q (%s) differ", key, placeholders(key), translated, placeholders(translated))
+		}
+	}
+}
+
+var messagePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:i18n\.|\b)(?:T|ReportT|Errorf)\(("(?:[^"\\]|\\.)*")`),
+	regexp.MustCompile(`\{\{t ("(?:[^"\\]|\\.)*")`),
+	regexp.MustCompile(`sarifMessage\{Text: ("(?:[^"\\]|\\.)*")`),
+	regexp.MustCompile(`newViolation\([^"]*?("(?:[^"\\]|\\.)*")`),
+}
+
+var scriptMessagePattern = regexp.MustCompile(`\bt\('((?:[^'\\]|\\.)*)'`)
+
+// TestCatalogCoverage проверяет, что у каждого сообщения в исходниках, в
+// шаблонах отчетов и в скриптах HTML-отчета есть английский перевод.
+func TestCatalogCoverage(t *testing.T) {
+	root := filepath.Join("..", "..")
+	checked := 0
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if name := info.Name(); path != root && (strings.HasPrefix(name, ".") || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var patterns []*regexp.Regexp
+		switch {
+		case strings.HasSuffix(path, "_test.go"):
+			return nil
+		case strings.HasSuffix(path, ".go"):
+			patterns = messagePatterns
+		case strings.HasSuffix(path, ".js"):
+			patterns = []*regexp.Regexp{scriptMessagePattern}
+		default:
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range patterns {
+			for _, match := range pattern.FindAllStringSubmatch(string(content), -1) {
+				message := match[1]
+				if pattern != scriptMessagePattern {
+					if message, err = strconv.Unquote(message); err != nil {
+						t.Errorf("%s: %v", path, err)
+						continue
+					}
+				}
+				if !regexp.MustCompile(`[А-Яа-яЁё]`).MatchString(message) {
+					continue
+				}
+				checked++
+				if _, ok := en[message]; !ok {
+					t.Errorf("%s: no en translation for %q", path, message)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked == 0 {
+		t.Error("no messages found")
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import "fsd-crawler/pkg/config"
+
+type DependencyType string
+
+const (
+	DependencyNormal    DependencyType = "normal"
+	DependencySameLayer DependencyType = "same"
+	DependencyCyclical  DependencyType = "cyclical"
+	DependencyTest      DependencyType = "test"
+)
+
+type ImportKind string
+
+const (
+	ImportStatic     ImportKind = "static"
+	ImportSideEffect ImportKind = "side-effect"
+	ImportDynamic    ImportKind = "dynamic"
+	ImportRequire    ImportKind = "require"
+	ImportReExport   ImportKind = "reexport"
+)
+
+type SymbolOrigin struct {
+	Name    string
+	File    string
+	Layer   string
+	Slice   string
+	Segment string
+	Chain   []string
+}
+
+type Dependency struct {
+	FromLayer   string
+	FromSlice   string
+	FromSegment string
+	ToLayer     string
+	ToSlice     string
+	ToSegment   string
+	Type        DependencyType
+	FromFile    string
+	ToFile      string
+	ViaFile     string // barrel-файл из импорта, если зависимость перенесена на источник имен
+	Line        int
+	ImportPath  string
+	ImportKind  ImportKind
+	Specifiers  []string
+	Origins     []SymbolOrigin
+}
+
+type APIIssueKind string
+
+const (
+	APIIssueUnusedExport APIIssueKind = "unused-export"
+	APIIssueNotExported  APIIssueKind = "not-exported"
+)
+
+type APIIssue struct {
+	Kind  APIIssueKind
+	Layer string
+	Slice string
+	Name  string
+	File  string
+	Line  int
+}
+
+type BarrelIssueKind string
+
+const (
+	BarrelIssueDeepChain  BarrelIssueKind = "deep-chain"
+	BarrelIssueCrossSlice BarrelIssueKind = "cross-slice-reexport"
+)
+
+type BarrelIssue struct {
+	Kind        BarrelIssueKind
+	Layer       string
+	Slice       string
+	File        string
+	Line        int
+	TargetLayer string
+	TargetSlice string
+	Chain       []string
+}
+
+type SegmentViolation struct {
+	Rule        config.SegmentRule
+	FromLayer   string
+	FromSlice   string
+	FromSegment string
+	ToLayer     string
+	ToSlice     string
+	ToSegment   string
+	File        string
+	Line        int
+	ImportPath  string
+}
+
+type SegmentCycle struct {
+	Layer    string
+	Slice    string
+	Segments []string
+	Imports  []Dependency
+}
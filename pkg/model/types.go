@@ -10,16 +10,32 @@ type FSDLayer struct {
 type FSDSlice struct {
 	Name     string
 	Segments []*FSDSegment
+	Exports  []string
 }
 
 type FSDSegment struct {
 	Name  string
 	Files []string
+	// Stats — размеры файлов сегмента по имени файла
+	Stats map[string]FileStats
+}
+
+// FileStats — объем файла: число строк и размер в байтах.
+type FileStats struct {
+	Lines int
+	Bytes int64
 }
 
 type ProjectStructure struct {
 	Layers []*FSDLayer
-	Dependencies []interface{}
+	Dependencies []Dependency
+	// InternalImports — относительные импорты внутри слайсов; в
+	// Dependencies и метрики они не входят
+	InternalImports []Dependency
+	APIIssues []APIIssue
+	BarrelIssues []BarrelIssue
+	SegmentViolations []SegmentViolation
+	SegmentCycles []SegmentCycle
 }
 
 var KnownLayers = []string{"app", "processes", "pages", "widgets", "features", "entities", "shared"}
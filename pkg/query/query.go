@@ -0,0 +1,234 @@
+package query
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+)
+
+type condition struct {
+	key    string
+	values []string
+	negate bool
+}
+
+type countCondition struct {
+	op    string
+	value int
+}
+
+// Query — разобранное выражение вида
+// "from:features/* to:entities/* type:cyclical count>3".
+type Query struct {
+	conditions []condition
+	counts     []countCondition
+}
+
+type Result struct {
+	From         string                      `json:"from"`
+	To           string                      `json:"to"`
+	Type         dependencies.DependencyType `json:"type"`
+	Count        int                         `json:"count"`
+	Dependencies []dependencies.Dependency   `json:"dependencies"`
+}
+
+var knownKeys = map[string]bool{
+	"from":  true,
+	"to":    true,
+	"layer": true,
+	"slice": true,
+	"type":  true,
+	"file":  true,
+	"kind":  true,
+}
+
+var countOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// Parse разбирает выражение запроса. Условия разделяются пробелами и
+// объединяются через И, альтернативы внутри условия перечисляются через
+// запятую, префикс "-" инвертирует условие.
+func Parse(expr string) (*Query, error) {
+	q := &Query{}
+
+	for _, term := range strings.Fields(expr) {
+		if strings.HasPrefix(term, "count") {
+			cond, err := parseCount(strings.TrimPrefix(term, "count"))
+			if err != nil {
+				return nil, i18n.Errorf("некорректное условие %q: %v", term, err)
+			}
+			q.counts = append(q.counts, cond)
+			continue
+		}
+
+		negate := strings.HasPrefix(term, "-")
+		term = strings.TrimPrefix(term, "-")
+
+		parts := strings.SplitN(term, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, i18n.Errorf("некорректное условие %q: ожидается ключ:значение", term)
+		}
+
+		key := strings.ToLower(parts[0])
+		if !knownKeys[key] {
+			return nil, i18n.Errorf("неизвестный ключ %q", parts[0])
+		}
+
+		values := strings.Split(parts[1], ",")
+		for _, value := range values {
+			if _, err := path.Match(value, ""); err != nil {
+				return nil, i18n.Errorf("некорректный шаблон %q: %v", value, err)
+			}
+		}
+
+		q.conditions = append(q.conditions, condition{key: key, values: values, negate: negate})
+	}
+
+	return q, nil
+}
+
+func parseCount(rest string) (countCondition, error) {
+	for _, op := range countOperators {
+		if strings.HasPrefix(rest, op) {
+			value, err := strconv.Atoi(strings.TrimPrefix(rest, op))
+			if err != nil {
+				return countCondition{}, i18n.Errorf("ожидается число")
+			}
+			return countCondition{op: op, value: value}, nil
+		}
+	}
+	return countCondition{}, i18n.Errorf("ожидается оператор сравнения (>, >=, <, <=, =, !=)")
+}
+
+func slicePath(layer, slice string) string {
+	if slice == "" || slice == layer {
+		return layer
+	}
+	return layer + "/" + slice
+}
+
+func matchAny(patterns []string, candidates ...string) bool {
+	for _, pattern := range patterns {
+		for _, candidate := range candidates {
+			if candidate == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c condition) match(dep dependencies.Dependency) bool {
+	from := slicePath(dep.FromLayer, dep.FromSlice)
+	to := slicePath(dep.ToLayer, dep.ToSlice)
+
+	var matched bool
+	switch c.key {
+	case "from":
+		matched = matchAny(c.values, from, dep.FromLayer, dep.FromFile)
+	case "to":
+		matched = matchAny(c.values, to, dep.ToLayer, dep.ToFile)
+	case "layer":
+		matched = matchAny(c.values, dep.FromLayer, dep.ToLayer)
+	case "slice":
+		matched = matchAny(c.values, from, to)
+	case "type":
+		matched = matchAny(c.values, string(dep.Type))
+	case "file":
+		matched = matchAny(c.values, dep.FromFile, dep.ToFile)
+	case "kind":
+		matched = matchAny(c.values, string(dep.ImportKind))
+	}
+
+	return matched != c.negate
+}
+
+func (c countCondition) match(count int) bool {
+	switch c.op {
+	case ">":
+		return count > c.value
+	case ">=":
+		return count >= c.value
+	case "<":
+		return count < c.value
+	case "<=":
+		return count <= c.value
+	case "=":
+		return count == c.value
+	case "!=":
+		return count != c.value
+	}
+	return false
+}
+
+// Match сообщает, удовлетворяет ли отдельная зависимость условиям запроса
+// (без учета условий count).
+func (q *Query) Match(dep dependencies.Dependency) bool {
+	for _, c := range q.conditions {
+		if !c.match(dep) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter возвращает зависимости, прошедшие запрос. Условия count
+// применяются к количеству импортов между парой слайсов.
+func (q *Query) Filter(deps []dependencies.Dependency) []dependencies.Dependency {
+	var filtered []dependencies.Dependency
+	for _, result := range q.Evaluate(deps) {
+		filtered = append(filtered, result.Dependencies...)
+	}
+	return filtered
+}
+
+// Evaluate группирует подходящие зависимости по паре слайсов и типу и
+// возвращает группы, удовлетворяющие условиям count.
+func (q *Query) Evaluate(deps []dependencies.Dependency) []Result {
+	groups := make(map[string]*Result)
+	var keys []string
+
+	for _, dep := range deps {
+		if !q.Match(dep) {
+			continue
+		}
+
+		from := slicePath(dep.FromLayer, dep.FromSlice)
+		to := slicePath(dep.ToLayer, dep.ToSlice)
+		key := from + "\x00" + to + "\x00" + string(dep.Type)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &Result{From: from, To: to, Type: dep.Type}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.Count++
+		group.Dependencies = append(group.Dependencies, dep)
+	}
+
+	sort.Strings(keys)
+
+	results := []Result{}
+	for _, key := range keys {
+		group := groups[key]
+		matched := true
+		for _, c := range q.counts {
+			if !c.match(group.Count) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			results = append(results, *group)
+		}
+	}
+
+	return results
+}
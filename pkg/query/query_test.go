@@ -0,0 +1,67 @@
+package query
+
+import (
+	"testing"
+
+	"fsd-crawler/pkg/dependencies"
+)
+
+func testDependencies() []dependencies.Dependency {
+	return []dependencies.Dependency{
+		{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", Type: dependencies.DependencyNormal, FromFile: "features/auth/model/auth.ts", ImportKind: dependencies.ImportStatic},
+		{FromLayer: "features", FromSlice: "auth", ToLayer: "entities", ToSlice: "user", Type: dependencies.DependencyNormal, FromFile: "features/auth/ui/Login.tsx", ImportKind: dependencies.ImportStatic},
+		{FromLayer: "features", FromSlice: "cart", ToLayer: "entities", ToSlice: "user", Type: dependencies.DependencyNormal, FromFile: "features/cart/model/cart.ts", ImportKind: dependencies.ImportDynamic},
+		{FromLayer: "entities", FromSlice: "user", ToLayer: "features", ToSlice: "auth", Type: dependencies.DependencyCyclical, FromFile: "entities/user/model/user.ts", ImportKind: dependencies.ImportStatic},
+		{FromLayer: "pages", FromSlice: "home", ToLayer: "shared", ToSlice: "ui", Type: dependencies.DependencyNormal, FromFile: "pages/home/ui/Home.tsx", ImportKind: dependencies.ImportStatic},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		expr    string
+		results int
+		imports int
+	}{
+		{"from:features/* to:entities/*", 2, 3},
+		{"from:features to:entities/user count>1", 1, 2},
+		{"type:cyclical", 1, 1},
+		{"-type:cyclical layer:entities", 2, 3},
+		{"to:shared,features", 2, 2},
+		{"slice:entities/user count>=2", 1, 2},
+		{"file:features/*/ui/*", 1, 1},
+		{"kind:dynamic", 1, 1},
+		{"from:widgets/*", 0, 0},
+	}
+
+	for _, tc := range testCases {
+		q, err := Parse(tc.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tc.expr, err)
+			continue
+		}
+
+		results := q.Evaluate(testDependencies())
+		if len(results) != tc.results {
+			t.Errorf("Evaluate(%q) returned %d results; want %d", tc.expr, len(results), tc.results)
+		}
+		if imports := len(q.Filter(testDependencies())); imports != tc.imports {
+			t.Errorf("Filter(%q) returned %d dependencies; want %d", tc.expr, imports, tc.imports)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"from",
+		"owner:team",
+		"count>many",
+		"count~3",
+		"to:[",
+	}
+
+	for _, expr := range invalid {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded; want error", expr)
+		}
+	}
+}
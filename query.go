@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"fsd-crawler/pkg/analyzer"
+	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/query"
+)
+
+func runQuery(args []string) int {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	format := flags.String("format", "text", i18n.T("формат вывода: text, json или csv — найденные связи, любой формат отчетов (html, dot, mermaid, sarif...) — отчет только с найденными зависимостями"))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler query [--format FORMAT] <выражение>"))
+		fmt.Fprintln(flags.Output(), i18n.T("Пример: fsd-crawler query 'from:features/* to:entities/* type:cyclical count>3'"))
+		fmt.Fprintln(flags.Output(), i18n.T("Ключи: from, to, layer, slice, type, file, kind, count (>, >=, <, <=, =, !=)."))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	q, err := query.Parse(strings.Join(flags.Args(), " "))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка в запросе: %v", err))
+		return 2
+	}
+
+	cfg := loadConfig()
+	structure := analyzer.AnalyzeProject(cfg)
+	deps := structure.Dependencies
+	results := q.Evaluate(deps)
+
+	switch *format {
+	case "text":
+		printQueryResults(os.Stdout, results)
+	case "json":
+		if err := writeQueryJSON(os.Stdout, results); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при кодировании в JSON: %v", err))
+			return 1
+		}
+	case "csv":
+		if err := writeQueryCSV(os.Stdout, results); err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при записи CSV: %v", err))
+			return 1
+		}
+	default:
+		if _, ok := exporter.Lookup(*format, cfg); !ok {
+			fmt.Fprintln(os.Stderr, i18n.T("Неподдерживаемый формат вывода: %s", *format))
+			return 2
+		}
+
+		// Остальные форматы строятся через реестр экспортеров по отчету,
+		// в котором остались только найденные зависимости
+		structure.Dependencies = q.Filter(deps)
+		outputPath, err := exporter.Run(*format, structure, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при экспорте в %s: %v", *format, err))
+			return 1
+		}
+		if outputPath != exporter.Stdout {
+			fmt.Println(i18n.T("Отчет сохранен в %s", outputPath))
+		}
+	}
+
+	return 0
+}
+
+func printQueryResults(w io.Writer, results []query.Result) {
+	imports := 0
+	for _, result := range results {
+		imports += result.Count
+		fmt.Fprintf(w, "%s → %s [%s] × %d\n", result.From, result.To, result.Type, result.Count)
+		for _, dep := range result.Dependencies {
+			fmt.Fprintf(w, "    %s:%d  %s\n", dep.FromFile, dep.Line, dep.ImportPath)
+		}
+	}
+	fmt.Fprintln(w, i18n.T("Найдено связей: %d (импортов: %d)", len(results), imports))
+}
+
+// queryJSONResult — связь в выводе query --format json. Зависимости
+// описываются ребрами JSON-отчета.
+type queryJSONResult struct {
+	From         string                `json:"from"`
+	To           string                `json:"to"`
+	Type         string                `json:"type"`
+	Count        int                   `json:"count"`
+	Dependencies []exporter.ReportEdge `json:"dependencies"`
+}
+
+func writeQueryJSON(w io.Writer, results []query.Result) error {
+	output := make([]queryJSONResult, 0, len(results))
+	for _, result := range results {
+		output = append(output, queryJSONResult{
+			From:         result.From,
+			To:           result.To,
+			Type:         string(result.Type),
+			Count:        result.Count,
+			Dependencies: exporter.ReportEdges(result.Dependencies),
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func writeQueryCSV(w io.Writer, results []query.Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"from", "to", "type", "count", "file", "line", "import"}); err != nil {
+		return err
+	}
+	for _, result := range results {
+		for _, dep := range result.Dependencies {
+			record := []string{
+				result.From,
+				result.To,
+				string(result.Type),
+				strconv.Itoa(result.Count),
+				dep.FromFile,
+				strconv.Itoa(dep.Line),
+				dep.ImportPath,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
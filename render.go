@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/i18n"
+	"fsd-crawler/pkg/model"
+)
+
+func runRender(args []string) int {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	formats := flags.String("format", "", i18n.T("форматы вывода через запятую (по умолчанию outputFormats из конфигурации)"))
+	outputDir := flags.String("out", "", i18n.T("директория для отчетов (по умолчанию outputDir из конфигурации)"))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler render [--format html,dot,...] [--out DIR] [fsd_structure.json]"))
+		fmt.Fprintln(flags.Output(), i18n.T("Строит отчеты по сохраненному JSON-отчету без повторного анализа исходников."))
+		fmt.Fprintln(flags.Output(), i18n.T("Без аргумента читается fsd_structure.json из outputDir."))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := loadConfig()
+
+	input := filepath.Join(cfg.OutputDir, "fsd_structure.json")
+	if flags.NArg() == 1 {
+		input = flags.Arg(0)
+	}
+	if *outputDir != "" {
+		cfg.OutputDir = *outputDir
+	}
+
+	file, err := os.Open(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при чтении отчета: %v", err))
+		return 1
+	}
+	report, err := exporter.ReadJSONReport(file)
+	file.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("Ошибка при чтении отчета %s: %v", input, err))
+		return 1
+	}
+
+	report.ApplyConfig(cfg)
+	model.UpdateFromConfig(cfg)
+	structure := report.Structure()
+
+	outputFormats := cfg.OutputFormats
+	if *formats != "" {
+		outputFormats = strings.Split(*formats, ",")
+	}
+	if len(outputFormats) == 0 {
+		outputFormats = []string{"html"}
+	}
+
+	status := 0
+	for _, format := range outputFormats {
+		format = strings.TrimSpace(format)
+		if _, ok := exporter.Lookup(format, cfg); !ok {
+			fmt.Fprintln(os.Stderr, i18n.T("Неподдерживаемый формат вывода: %s", format))
+			status = 1
+			continue
+		}
+
+		outputPath, err := exporter.Run(format, structure, cfg)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("Ошибка при экспорте в %s: %v", format, err))
+			status = 1
+			continue
+		}
+		if outputPath != exporter.Stdout {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", format, outputPath)
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"fsd-crawler/pkg/exporter"
+	"fsd-crawler/pkg/i18n"
+)
+
+func runSchema(args []string) int {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler schema"))
+		fmt.Fprintln(flags.Output(), i18n.T("Выводит JSON Schema отчета формата json (версия %s).", exporter.ReportSchemaVersion))
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if _, err := os.Stdout.Write(exporter.ReportSchema); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("не удалось вывести схему: %v", err))
+		return 1
+	}
+	return 0
+}
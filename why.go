@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"fsd-crawler/pkg/analyzer"
+	"fsd-crawler/pkg/dependencies"
+	"fsd-crawler/pkg/i18n"
+)
+
+// defaultWhyLimit — число кратчайших путей по умолчанию: полный перебор
+// простых путей на реальных графах экспоненциален по времени и памяти.
+const defaultWhyLimit = 10
+
+func runWhy(args []string) int {
+	flags := flag.NewFlagSet("why", flag.ContinueOnError)
+	limit := flags.Int("k", defaultWhyLimit, i18n.T("вывести только k кратчайших путей (0 — все пути, их число растет экспоненциально)"))
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), i18n.T("Использование: fsd-crawler why [-k N] <откуда> <куда>"))
+		fmt.Fprintln(flags.Output(), i18n.T("Узлы задаются как слайсы (pages/home) или файлы (pages/home/ui/HomePage.tsx)."))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := loadConfig()
+	structure := analyzer.AnalyzeProject(cfg)
+	from := dependencies.NewPathNode(structure, flags.Arg(0))
+	to := dependencies.NewPathNode(structure, flags.Arg(1))
+
+	// Импорты внутри слайсов нужны для путей между файлами
+	imports := append(append([]dependencies.Dependency{}, structure.Dependencies...), structure.InternalImports...)
+	paths := dependencies.FindPaths(imports, from, to, *limit)
+	printPaths(os.Stdout, from.Path, to.Path, paths)
+
+	if len(paths) == 0 {
+		return 1
+	}
+	return 0
+}
+
+func printPaths(w io.Writer, from, to string, paths []dependencies.DependencyPath) {
+	if len(paths) == 0 {
+		fmt.Fprintln(w, i18n.T("%s не зависит от %s", from, to))
+		return
+	}
+
+	fmt.Fprintln(w, i18n.T("%s → %s: найдено путей: %d", from, to, len(paths)))
+
+	for i, path := range paths {
+		nodes := []string{path[0].From}
+		for _, hop := range path {
+			nodes = append(nodes, hop.To)
+		}
+		fmt.Fprintf(w, "\n%d. %s\n", i+1, strings.Join(nodes, " → "))
+
+		for _, hop := range path {
+			fmt.Fprintf(w, "   %s → %s\n", hop.From, hop.To)
+			for _, dep := range hop.Imports {
+				fmt.Fprintf(w, "      %s:%d  %s\n", dep.FromFile, dep.Line, dep.ImportPath)
+			}
+		}
+	}
+}